@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"log"
 	"os"
 
@@ -13,6 +15,10 @@ type Config struct {
 	JWT        jwtEnvironment
 	Redis      RedisConfig
 	Cloudinary CloudinaryConfig
+	Timeline   TimelineConfig
+	Storage    StorageConfig
+	Reaper     MediaReaperConfig
+	Trending   TrendingTagsConfig
 }
 
 type server struct {
@@ -21,16 +27,19 @@ type server struct {
 }
 
 func InitializeConfig() *Config {
+	// tanpa file .env konfigurasi diambil dari environment process saja
 	err := godotenv.Load()
-	if err != nil {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatalf("error when load env %s", err.Error())
 	}
 
+	appServer := server{
+		Host: os.Getenv("APP_HOST"),
+		Port: os.Getenv("APP_PORT"),
+	}
+
 	return &Config{
-		server{
-			Host: os.Getenv("APP_HOST"),
-			Port: os.Getenv("APP_PORT"),
-		},
+		appServer,
 		database{
 			Host:     os.Getenv("PG_HOST"),
 			Port:     os.Getenv("PG_PORT"),
@@ -43,6 +52,8 @@ func InitializeConfig() *Config {
 			JWTRefreshKey: os.Getenv("REFRESH_KEY"),
 			TokenExpiry:   os.Getenv("TOKEN_EXPIRY"),
 			RefreshExpiry: os.Getenv("REFRESH_EXPIRY"),
+			SigningKeys:   os.Getenv("JWT_SIGNING_KEYS"),
+			SigningKid:    os.Getenv("JWT_SIGNING_KID"),
 		},
 		RedisConfig{
 			Host:     os.Getenv("REDIS_HOST"),
@@ -54,6 +65,10 @@ func InitializeConfig() *Config {
 			APIKey:    os.Getenv("CLOUDINARY_API_KEY"),
 			APISecret: os.Getenv("CLOUDINARY_API_SECRET"),
 		},
+		loadTimelineConfig(),
+		loadStorageConfig(appServer),
+		loadMediaReaperConfig(),
+		loadTrendingTagsConfig(),
 	}
 
 }
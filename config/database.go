@@ -32,7 +32,8 @@ func InitializeDB(cnf *Config) *gorm.DB {
 		cnf.Database.Port,
 	)
 
-	db, err := gorm.Open(postgres.Open(psqlInfo), &gorm.Config{})
+	// TranslateError membuat pelanggaran unique index bisa dicek dengan gorm.ErrDuplicatedKey
+	db, err := gorm.Open(postgres.Open(psqlInfo), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("error connecting database = ", err)
 	}
@@ -40,12 +41,19 @@ func InitializeDB(cnf *Config) *gorm.DB {
 	db.AutoMigrate(
 		&domain.User{},
 		&domain.Photo{},
+		&domain.PhotoMedia{},
+		&domain.Media{},
 		&domain.Comment{},
+		&domain.CommentLike{},
+		&domain.Mention{},
 		&domain.UserLikesPhoto{},
 		&domain.Authentication{},
 		&domain.Tag{},
+		&domain.PhotoTags{},
 		&domain.Follow{},
 	)
 
+	migrateData(db)
+
 	return db
 }
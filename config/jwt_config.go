@@ -1,8 +1,9 @@
 package config
 
 import (
-	"fmt"
+	"log"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,16 @@ type jwtEnvironment struct {
 	JWTRefreshKey string
 	TokenExpiry   string
 	RefreshExpiry string
+	// SigningKeys berisi daftar kid=path file private key PEM dipisah koma,
+	// contoh: 2024-01=keys/rsa.pem,2024-06=keys/ed25519.pem
+	SigningKeys string
+	SigningKid  string
+}
+
+// SigningKeyFile adalah satu private key untuk menandatangani access token
+type SigningKeyFile struct {
+	Kid  string
+	Path string
 }
 
 type jwtConfig struct {
@@ -34,7 +45,7 @@ func (c *jwtConfig) GetRefreshKey() string {
 func (c *jwtConfig) GetTokenExpiry() time.Duration {
 	convertTokenExpiryToInt, err := strconv.Atoi(c.cfg.JWT.TokenExpiry)
 	if err != nil {
-		fmt.Errorf("error converting token expiry %s ", err.Error())
+		log.Printf("error converting token expiry %s ", err.Error())
 	}
 	tokenExpiry := time.Duration(convertTokenExpiryToInt) * time.Minute
 	return tokenExpiry
@@ -43,8 +54,34 @@ func (c *jwtConfig) GetTokenExpiry() time.Duration {
 func (c *jwtConfig) GetRefreshExpiry() time.Duration {
 	convertRefreshExpiryToInt, err := strconv.Atoi(c.cfg.JWT.RefreshExpiry)
 	if err != nil {
-		fmt.Errorf("error converting refresh expiry %s ", err.Error())
+		log.Printf("error converting refresh expiry %s ", err.Error())
 	}
 	refreshExpiry := time.Duration(convertRefreshExpiryToInt) * time.Minute
 	return refreshExpiry
 }
+
+func (c *jwtConfig) GetSigningKeys() []SigningKeyFile {
+	var keys []SigningKeyFile
+
+	for _, entry := range strings.Split(c.cfg.JWT.SigningKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(entry, "=")
+		if !found || kid == "" || path == "" {
+			log.Fatalf("invalid JWT_SIGNING_KEYS entry %q, expected kid=path", entry)
+		}
+
+		keys = append(keys, SigningKeyFile{Kid: strings.TrimSpace(kid), Path: strings.TrimSpace(path)})
+	}
+
+	return keys
+}
+
+// GetSigningKid adalah kid yang dipakai untuk menandatangani token baru,
+// kunci lain di JWT_SIGNING_KEYS hanya dipakai untuk verifikasi selama rotasi
+func (c *jwtConfig) GetSigningKid() string {
+	return c.cfg.JWT.SigningKid
+}
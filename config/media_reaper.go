@@ -0,0 +1,51 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	defaultMediaReaperInterval = time.Hour
+	defaultMediaReaperMaxAge   = 24 * time.Hour
+)
+
+// MediaReaperConfig mengatur pembersihan file upload yang tidak pernah dipakai.
+// Upload yang lebih muda dari MaxAge tidak disentuh karena bisa jadi masih akan dipakai.
+// DryRun hanya mencatat file yang akan dihapus tanpa menghapus apa pun
+type MediaReaperConfig struct {
+	Interval time.Duration
+	MaxAge   time.Duration
+	DryRun   bool
+}
+
+func loadMediaReaperConfig() MediaReaperConfig {
+	cfg := MediaReaperConfig{
+		Interval: getEnvDuration("MEDIA_REAPER_INTERVAL", defaultMediaReaperInterval),
+		MaxAge:   getEnvDuration("MEDIA_REAPER_MAX_AGE", defaultMediaReaperMaxAge),
+		DryRun:   os.Getenv("MEDIA_REAPER_DRY_RUN") == "true",
+	}
+
+	// max age 0 akan menghapus upload yang baru selesai sebelum sempat dipakai
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = defaultMediaReaperMaxAge
+	}
+
+	return cfg
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsedValue, err := time.ParseDuration(value)
+	if err != nil || parsedValue < 0 {
+		log.Printf("invalid value for %s, using default %s", key, fallback)
+		return fallback
+	}
+
+	return parsedValue
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"log"
+	"unicode/utf16"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"gorm.io/gorm"
+)
+
+// migrateData menjalankan perbaikan data yang tidak bisa dilakukan AutoMigrate,
+// setiap langkah harus aman dijalankan berulang kali setiap aplikasi start
+func migrateData(db *gorm.DB) {
+	steps := []struct {
+		name string
+		run  func(tx *gorm.DB) error
+	}{
+		{"backfill follows.date_followed", backfillFollowDates},
+		{"unique media.public_id", uniqueMediaPublicId},
+		{"mention offsets in utf-16", convertMentionOffsets},
+		{"normalize tags.name", normalizeTags},
+		{"backfill photo_tags.created_at", backfillPhotoTagDates},
+	}
+
+	for _, step := range steps {
+		if err := db.Transaction(step.run); err != nil {
+			log.Fatalf("error migrating data (%s) = %v", step.name, err)
+		}
+	}
+}
+
+// backfillFollowDates mengisi date_followed yang masih NULL dari data lama,
+// tanggal follow tidak mungkin lebih awal dari tanggal kedua akun dibuat
+func backfillFollowDates(tx *gorm.DB) error {
+	return tx.Exec(`
+		UPDATE follows
+		SET date_followed = COALESCE(GREATEST(
+			(SELECT created_at FROM users WHERE users.id = follows.follower_id),
+			(SELECT created_at FROM users WHERE users.id = follows.following_id)
+		), NOW())
+		WHERE date_followed IS NULL`).Error
+}
+
+// uniqueMediaPublicId membuang baris media ganda dari konfirmasi upload yang diulang, lalu memasang
+// unique index. Index dibuat di sini, bukan lewat tag gorm, karena AutoMigrate jalan sebelum data dibersihkan.
+// Baris yang dipertahankan adalah yang sudah di-claim, kalau sama-sama belum yang paling lama
+func uniqueMediaPublicId(tx *gorm.DB) error {
+	err := tx.Exec(`
+		DELETE FROM media
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY public_id ORDER BY claimed DESC, created_at ASC, id ASC) AS position
+				FROM media
+			) ranked
+			WHERE position > 1
+		)`).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_media_public_id ON media (public_id)`).Error
+}
+
+// convertMentionOffsets mengubah offset dan panjang mention lama dari rune ke UTF-16 code unit
+// berdasarkan caption atau comment tempat mention itu berada
+func convertMentionOffsets(tx *gorm.DB) error {
+	var mentions []struct {
+		ID         uint
+		TextOffset int
+		Length     int
+		Text       string
+	}
+
+	err := tx.Raw(`
+		SELECT mentions.id, mentions.text_offset, mentions.length, COALESCE(photos.caption, comments.message, '') AS text
+		FROM mentions
+		LEFT JOIN photos ON photos.id = mentions.photo_id
+		LEFT JOIN comments ON comments.id = mentions.comment_id
+		WHERE mentions.offset_utf16 = false`).Scan(&mentions).Error
+	if err != nil {
+		return err
+	}
+
+	for _, mention := range mentions {
+		runes := []rune(mention.Text)
+		start := min(mention.TextOffset, len(runes))
+		end := min(mention.TextOffset+mention.Length, len(runes))
+
+		offset := utf16Length(runes[:start])
+		err = tx.Model(&domain.Mention{}).Where("id = ?", mention.ID).Updates(map[string]interface{}{
+			"text_offset":  offset,
+			"length":       utf16Length(runes[:end]) - offset,
+			"offset_utf16": true,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeTags menormalisasi nama tag lama dengan aturan yang sama seperti tag baru, menggabungkan
+// tag yang hasilnya sama ke id terkecil lalu memasang unique index. Tag yang tidak valid dihapus beserta relasinya.
+// Langkah ini dilewati kalau index sudah ada karena semua tag setelahnya sudah dinormalisasi saat dibuat
+func normalizeTags(tx *gorm.DB) error {
+	if tx.Migrator().HasIndex(&domain.Tag{}, "idx_tags_name") {
+		return nil
+	}
+
+	var tags []domain.Tag
+	err := tx.Select("id", "name").Order("id ASC").Find(&tags).Error
+	if err != nil {
+		return err
+	}
+
+	canonicalIds := make(map[string]uint)
+	var renamed []domain.Tag
+	for _, tag := range tags {
+		name := domain.NormalizeTag(tag.Name)
+		if name == "" {
+			if err := deleteTag(tx, tag.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		canonicalId, exists := canonicalIds[name]
+		if !exists {
+			canonicalIds[name] = tag.ID
+			if name != tag.Name {
+				renamed = append(renamed, domain.Tag{ID: tag.ID, Name: name})
+			}
+			continue
+		}
+
+		// pindahkan foto ke tag utama tanpa membuat pasangan photo_id dan tag_id ganda
+		err = tx.Exec(`
+			INSERT INTO photo_tags (photo_id, tag_id)
+			SELECT DISTINCT photo_id, ? FROM photo_tags merged
+			WHERE merged.tag_id = ? AND NOT EXISTS (
+				SELECT 1 FROM photo_tags existing WHERE existing.photo_id = merged.photo_id AND existing.tag_id = ?
+			)`, canonicalId, tag.ID, canonicalId).Error
+		if err != nil {
+			return err
+		}
+
+		if err := deleteTag(tx, tag.ID); err != nil {
+			return err
+		}
+	}
+
+	// nama diubah setelah tag ganda dihapus supaya tidak bentrok dengan nama yang sudah ternormalisasi
+	for _, tag := range renamed {
+		err = tx.Model(&domain.Tag{}).Where("id = ?", tag.ID).Update("name", tag.Name).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name)`).Error
+}
+
+// backfillPhotoTagDates mengisi created_at relasi tag lama dengan waktu foto dibuat,
+// relasi lama tidak pernah berubah setelah foto dibuat karena update dulu menghapus dan membuat ulang semuanya
+func backfillPhotoTagDates(tx *gorm.DB) error {
+	return tx.Exec(`
+		UPDATE photo_tags
+		SET created_at = COALESCE((SELECT created_at FROM photos WHERE photos.id = photo_tags.photo_id), NOW())
+		WHERE created_at IS NULL`).Error
+}
+
+func deleteTag(tx *gorm.DB, id uint) error {
+	err := tx.Where("tag_id = ?", id).Delete(&domain.PhotoTags{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Delete(&domain.Tag{}, id).Error
+}
+
+func utf16Length(runes []rune) int {
+	length := 0
+	for _, r := range runes {
+		length += utf16.RuneLen(r)
+	}
+
+	return length
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// publicReadPolicy membuka akses baca anonim untuk folder foto supaya url publiknya bisa dibuka
+const publicReadPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/mygram-image/*"]}]}`
+
+// ConnectS3 membuat client untuk storage yang kompatibel dengan S3, misalnya MinIO.
+// Bucket dibuat otomatis kalau belum ada
+func ConnectS3(cfg *Config) (*minio.Client, error) {
+	client, err := minio.New(cfg.Storage.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.Storage.S3AccessKey, cfg.Storage.S3SecretKey, ""),
+		Secure: cfg.Storage.S3UseSSL,
+		Region: cfg.Storage.S3Region,
+	})
+	if err != nil {
+		return client, err
+	}
+
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, cfg.Storage.S3Bucket)
+	if err != nil {
+		return client, err
+	}
+
+	if !exists {
+		err = client.MakeBucket(ctx, cfg.Storage.S3Bucket, minio.MakeBucketOptions{Region: cfg.Storage.S3Region})
+		if err != nil {
+			return client, err
+		}
+
+		err = client.SetBucketPolicy(ctx, cfg.Storage.S3Bucket, fmt.Sprintf(publicReadPolicy, cfg.Storage.S3Bucket))
+		if err != nil {
+			return client, err
+		}
+	}
+
+	return client, nil
+}
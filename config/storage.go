@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	STORAGE_CLOUDINARY = "cloudinary"
+	STORAGE_LOCAL      = "local"
+	STORAGE_S3         = "s3"
+
+	// LocalStorageRoute adalah route gin yang menyajikan file dari driver local
+	LocalStorageRoute = "/uploads"
+)
+
+// StorageConfig memilih tempat penyimpanan file upload lewat STORAGE_DRIVER.
+// Driver local cocok untuk development dan test karena tidak butuh koneksi keluar
+type StorageConfig struct {
+	Driver string
+
+	LocalDir string
+	LocalURL string
+
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3Bucket    string
+	S3Region    string
+	S3UseSSL    bool
+	S3PublicURL string
+
+	// UploadTempDir menampung potongan file dari resumable upload sampai di-finalize
+	UploadTempDir string
+}
+
+func loadStorageConfig(server server) StorageConfig {
+	cfg := StorageConfig{
+		Driver:      getEnvString("STORAGE_DRIVER", STORAGE_CLOUDINARY),
+		LocalDir:    getEnvString("STORAGE_LOCAL_DIR", "./uploads"),
+		LocalURL:    os.Getenv("STORAGE_LOCAL_URL"),
+		S3Endpoint:  os.Getenv("STORAGE_S3_ENDPOINT"),
+		S3AccessKey: os.Getenv("STORAGE_S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("STORAGE_S3_SECRET_KEY"),
+		S3Bucket:    getEnvString("STORAGE_S3_BUCKET", "mygram"),
+		S3Region:    getEnvString("STORAGE_S3_REGION", "us-east-1"),
+		S3UseSSL:    os.Getenv("STORAGE_S3_USE_SSL") == "true",
+		S3PublicURL: os.Getenv("STORAGE_S3_PUBLIC_URL"),
+
+		UploadTempDir: getEnvString("UPLOAD_TEMP_DIR", filepath.Join(os.TempDir(), "mygram-uploads")),
+	}
+
+	if cfg.LocalURL == "" {
+		host := server.Host
+		if host == "" {
+			host = "localhost"
+		}
+		cfg.LocalURL = "http://" + host + ":" + server.Port + LocalStorageRoute
+	}
+
+	// default url publik mengikuti path-style minio: <endpoint>/<bucket>
+	if cfg.S3PublicURL == "" && cfg.S3Endpoint != "" {
+		scheme := "http://"
+		if cfg.S3UseSSL {
+			scheme = "https://"
+		}
+		cfg.S3PublicURL = scheme + cfg.S3Endpoint + "/" + cfg.S3Bucket
+	}
+
+	cfg.LocalURL = strings.TrimSuffix(cfg.LocalURL, "/")
+	cfg.S3PublicURL = strings.TrimSuffix(cfg.S3PublicURL, "/")
+
+	return cfg
+}
+
+func getEnvString(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
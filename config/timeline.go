@@ -0,0 +1,42 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	defaultTimelineMaxLength       = 800
+	defaultTimelineFanOutThreshold = 10000
+)
+
+// TimelineConfig mengatur timeline home feed yang disimpan di redis.
+// User dengan follower lebih dari FanOutThreshold tidak di fan-out saat upload,
+// fotonya diambil langsung dari database saat feed dibaca
+type TimelineConfig struct {
+	MaxLength       int64
+	FanOutThreshold int64
+}
+
+func loadTimelineConfig() TimelineConfig {
+	return TimelineConfig{
+		MaxLength:       getEnvInt64("TIMELINE_MAX_LENGTH", defaultTimelineMaxLength),
+		FanOutThreshold: getEnvInt64("TIMELINE_FANOUT_THRESHOLD", defaultTimelineFanOutThreshold),
+	}
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsedValue, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("invalid value for %s, using default %d", key, fallback)
+		return fallback
+	}
+
+	return parsedValue
+}
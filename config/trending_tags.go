@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+const (
+	defaultTrendingTagsWindow   = 24 * time.Hour
+	defaultTrendingTagsCacheTTL = 10 * time.Minute
+)
+
+// TrendingTagsConfig mengatur perhitungan tag trending. Pemakaian tag di Window terakhir
+// dibandingkan dengan Window sebelumnya, hasilnya disimpan di redis selama CacheTTL
+type TrendingTagsConfig struct {
+	Window   time.Duration
+	CacheTTL time.Duration
+}
+
+func loadTrendingTagsConfig() TrendingTagsConfig {
+	cfg := TrendingTagsConfig{
+		Window:   getEnvDuration("TRENDING_TAGS_WINDOW", defaultTrendingTagsWindow),
+		CacheTTL: getEnvDuration("TRENDING_TAGS_CACHE_TTL", defaultTrendingTagsCacheTTL),
+	}
+
+	if cfg.Window == 0 {
+		cfg.Window = defaultTrendingTagsWindow
+	}
+
+	return cfg
+}
@@ -1,5 +1,19 @@
 package domain
 
+import "time"
+
+// Authentication adalah satu refresh token. Token hasil rotasi tetap disimpan
+// dengan RotatedAt terisi supaya pemakaian ulang token lama bisa dideteksi,
+// sampai umurnya melewati umur refresh token dan dihapus saat family-nya dirotasi lagi.
+// Satu family sama dengan satu sesi login, CreatedAt ikut disalin saat rotasi
+// sehingga menunjukkan kapan sesi dimulai
 type Authentication struct {
-	RefreshToken string `gorm:"type:text" json:"refresh_token"`
+	RefreshToken string     `gorm:"type:text;index" json:"refresh_token"`
+	UserId       uint       `gorm:"index" json:"-"`
+	FamilyId     string     `gorm:"type:uuid;index" json:"-"`
+	UserAgent    string     `json:"-"`
+	IpAddress    string     `json:"-"`
+	RotatedAt    *time.Time `json:"-"`
+	CreatedAt    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+	LastUsedAt   time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
 }
@@ -14,4 +14,30 @@ type Comment struct {
 	UpdatedAt *time.Time
 	User      User  `gorm:"foreignKey:UserId" json:"-"`
 	Photo     Photo `gorm:"foreignKey:PhotoId" json:"-"`
+
+	// ParentId hanya terisi untuk balasan, balasan selalu menempel ke comment level atas
+	ParentId     *uint     `gorm:"index" json:"parent_id"`
+	Replies      []Comment `gorm:"foreignKey:ParentId;constraint:OnDelete:CASCADE" json:"-"`
+	TotalReplies int64     `gorm:"-" json:"total_replies"`
+
+	// TotalLikes disimpan di tabel supaya comment bisa diurutkan berdasarkan like
+	TotalLikes int64         `gorm:"not null;default:0" json:"total_likes"`
+	LikedByMe  bool          `gorm:"-" json:"liked_by_me"`
+	Likes      []CommentLike `gorm:"foreignKey:CommentId;constraint:OnDelete:CASCADE" json:"-"`
+
+	Mentions []Mention `gorm:"foreignKey:CommentId;constraint:OnDelete:CASCADE" json:"mentions,omitempty"`
+}
+
+const (
+	CommentSortNewest = "newest"
+	CommentSortTop    = "top"
+)
+
+func IsValidCommentSort(sort string) bool {
+	switch sort {
+	case CommentSortNewest, CommentSortTop:
+		return true
+	}
+
+	return false
 }
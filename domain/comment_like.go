@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type CommentLike struct {
+	ID        uint `gorm:"primaryKey" json:"id"`
+	UserId    uint `gorm:"not null;uniqueIndex:idx_comment_likes_user_comment" json:"user_id"`
+	CommentId uint `gorm:"not null;uniqueIndex:idx_comment_likes_user_comment" json:"comment_id"`
+	CreatedAt *time.Time
+}
@@ -9,8 +9,31 @@ type UserRegister struct {
 type UserLogin struct {
 	Username string `validate:"required,min=3" json:"username"`
 	Password string `validate:"required,min=8" json:"password"`
+	// diisi handler dari request
+	UserAgent string `json:"-"`
+	IpAddress string `json:"-"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	UserAgent    string `json:"-"`
+	IpAddress    string `json:"-"`
 }
 
 type ResendEmailRequest struct {
 	Email string `validate:"required,email" json:"email"`
 }
+
+type ForgotPasswordRequest struct {
+	Email string `validate:"required,email" json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Email    string `validate:"required,email" json:"email"`
+	Code     string `validate:"required" json:"code"`
+	Password string `validate:"required,min=8" json:"password"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `validate:"required" json:"role"`
+}
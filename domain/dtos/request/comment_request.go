@@ -4,4 +4,6 @@ type CommentRequest struct {
 	Message string `validate:"required" json:"message"`
 	PhotoId string
 	UserId  uint
+	// ParentId diisi kalau comment ini adalah balasan, diabaikan saat update
+	ParentId *uint `json:"parent_id"`
 }
@@ -1,12 +1,22 @@
 package request
 
 type PhotoRequest struct {
-	Caption  string   `json:"caption"`
-	PhotoUrl string   `json:"photo_url"`
-	Tags     []string `json:"tags"`
+	Caption string `json:"caption"`
+	// MediaId adalah id hasil upload, dipakai kalau postingan hanya berisi satu slide
+	MediaId string   `json:"media_id"`
+	Tags    []string `json:"tags"`
+	// Media berisi slide carousel sesuai urutan, kalau kosong dipakai media id di atas sebagai satu slide
+	Media []PhotoMediaRequest `json:"media"`
+}
+
+type PhotoMediaRequest struct {
+	MediaId string `json:"media_id"`
+	AltText string `json:"alt_text"`
 }
 
 type UpdatePhotoRequest struct {
 	Caption string   `json:"caption"`
 	Tags    []string `json:"tags"`
+	// Media adalah id slide dengan urutan baru, slide yang tidak disebut akan dihapus
+	Media []string `json:"media"`
 }
@@ -0,0 +1,10 @@
+package request
+
+type PresignUploadRequest struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type ConfirmUploadRequest struct {
+	Url string `json:"url"`
+}
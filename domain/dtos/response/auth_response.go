@@ -1,6 +1,17 @@
 package response
 
+import "time"
+
 type LoginResponse struct {
 	AccessToken  string `jaon:"access_token"`
 	RefreshToken string `jaon:"refresh_token"`
 }
+
+type SessionResponse struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
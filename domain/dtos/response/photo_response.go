@@ -1,14 +1,49 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
 
 type PhotoResponse struct {
-	Id            string     `json:"id"`
-	Caption       string     `json:"caption"`
-	PhotoUrl      string     `json:"photo_url"`
-	PhotoTags     []string   `json:"photo_tags,omitempty"`
-	TotalLikes    int64      `json:"total_likes"`
-	TotalComments int64      `json:"total_comments"`
-	Username      string     `json:"username"`
-	CreatedAt     *time.Time `json:"created_at"`
+	Id            string               `json:"id"`
+	Caption       string               `json:"caption"`
+	MediaType     string               `json:"media_type"`
+	PhotoUrl      string               `json:"photo_url"`
+	PosterUrl     string               `json:"poster_url,omitempty"`
+	MediumUrl     string               `json:"medium_url,omitempty"`
+	ThumbnailUrl  string               `json:"thumbnail_url,omitempty"`
+	Width         int                  `json:"width,omitempty"`
+	Height        int                  `json:"height,omitempty"`
+	BlurHash      string               `json:"blur_hash,omitempty"`
+	DominantColor string               `json:"dominant_color,omitempty"`
+	PhotoTags     []string             `json:"photo_tags,omitempty"`
+	Mentions      []domain.Mention     `json:"mentions,omitempty"`
+	Media         []PhotoMediaResponse `json:"media"`
+	TotalLikes    int64                `json:"total_likes"`
+	TotalComments int64                `json:"total_comments"`
+	Username      string               `json:"username"`
+	CreatedAt     *time.Time           `json:"created_at"`
+}
+
+type PhotoMediaResponse struct {
+	Id            string  `json:"id"`
+	MediaType     string  `json:"media_type"`
+	PhotoUrl      string  `json:"photo_url"`
+	PosterUrl     string  `json:"poster_url,omitempty"`
+	MediumUrl     string  `json:"medium_url,omitempty"`
+	ThumbnailUrl  string  `json:"thumbnail_url,omitempty"`
+	Width         int     `json:"width,omitempty"`
+	Height        int     `json:"height,omitempty"`
+	BlurHash      string  `json:"blur_hash,omitempty"`
+	DominantColor string  `json:"dominant_color,omitempty"`
+	Duration      float64 `json:"duration,omitempty"`
+	AltText       string  `json:"alt_text,omitempty"`
+}
+
+// SimilarPhotoResponse adalah foto yang mirip beserta jarak hamming terkecil antar slide-nya
+type SimilarPhotoResponse struct {
+	PhotoResponse
+	Distance int `json:"distance"`
 }
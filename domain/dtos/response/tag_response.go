@@ -0,0 +1,7 @@
+package response
+
+type TagResponse struct {
+	Id          uint   `json:"id"`
+	Name        string `json:"name"`
+	TotalPhotos int64  `json:"total_photos"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type UploadFileResponse struct {
+	// Id adalah id media yang dikirim saat membuat postingan
+	Id            string  `json:"id"`
+	MediaType     string  `json:"media_type"`
+	PhotoUrl      string  `json:"photo_url"`
+	PosterUrl     string  `json:"poster_url,omitempty"`
+	MediumUrl     string  `json:"medium_url"`
+	ThumbnailUrl  string  `json:"thumbnail_url"`
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	BlurHash      string  `json:"blur_hash,omitempty"`
+	DominantColor string  `json:"dominant_color,omitempty"`
+	Duration      float64 `json:"duration,omitempty"`
+}
+
+type PresignedUploadResponse struct {
+	UploadUrl string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	FileUrl   string            `json:"file_url"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Media adalah file yang sudah diupload user. Media baru menjadi slide setelah di-claim
+// oleh sebuah postingan, dan satu media hanya bisa di-claim sekali.
+// PublicId adalah key file di storage, unique index-nya dibuat di config/migration.go
+type Media struct {
+	ID           string     `gorm:"primaryKey" json:"id"`
+	UserId       uint       `gorm:"not null;index" json:"user_id"`
+	PublicId     string     `gorm:"not null" json:"public_id"`
+	MimeType     string     `gorm:"not null" json:"mime_type"`
+	Size         int64      `gorm:"not null" json:"size"`
+	MediaType    string     `gorm:"not null;default:image" json:"media_type"`
+	PhotoUrl     string     `gorm:"not null;index" json:"photo_url"`
+	PosterUrl    string     `gorm:"index" json:"poster_url"`
+	MediumUrl    string     `gorm:"index" json:"medium_url"`
+	ThumbnailUrl string     `gorm:"index" json:"thumbnail_url"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     float64    `json:"duration"`
+	Claimed      bool       `gorm:"not null;default:false;index" json:"claimed"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+
+	// PerceptualHash adalah dHash gambar (disimpan sebagai bigint), 0 berarti tidak ada hash
+	PerceptualHash int64  `gorm:"not null;default:0" json:"-"`
+	BlurHash       string `json:"blur_hash"`
+	DominantColor  string `json:"dominant_color"`
+}
+
+func (Media) TableName() string {
+	return "media"
+}
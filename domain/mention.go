@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Mention adalah @username di caption foto atau di comment. PhotoId hanya terisi
+// untuk caption dan CommentId hanya terisi untuk comment. Offset dan Length dalam UTF-16 code unit.
+// OffsetUtf16 menandai baris yang sudah memakai UTF-16, baris lama dalam rune diubah oleh config/migration.go
+type Mention struct {
+	ID        uint       `gorm:"primaryKey" json:"-"`
+	PhotoId   *string    `gorm:"index" json:"-"`
+	CommentId *uint      `gorm:"index" json:"-"`
+	UserId    uint       `gorm:"not null;index" json:"user_id"`
+	Offset    int        `gorm:"column:text_offset;not null" json:"offset"`
+	Length    int        `gorm:"not null" json:"length"`
+	CreatedAt *time.Time `json:"-"`
+
+	OffsetUtf16 bool `gorm:"not null;default:false" json:"-"`
+}
+
+// MentionEvent dikirim ke stream notifikasi setiap kali user di-mention
+type MentionEvent struct {
+	Type            string    `json:"type"`
+	MentionedUserId uint      `json:"mentioned_user_id"`
+	AuthorId        uint      `json:"author_id"`
+	PhotoId         string    `json:"photo_id"`
+	CommentId       *uint     `json:"comment_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+const EventTypeMention = "mention"
@@ -6,15 +6,29 @@ import (
 
 // Photo represents the model for an Photo
 type Photo struct {
-	ID           string     `gorm:"primaryKey" json:"id"`
-	Caption      string     `json:"caption"`
-	PhotoUrl     string     `gorm:"not null" json:"photo_url"`
-	UserId       uint       `json:"user_id"`
-	CreatedAt    *time.Time `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
-	User         User       `gorm:"foreignKey:UserId" json:"-"`
-	TotalComment int64      `gorm:"-" json:"total_comment"`
-	Comments     []Comment  `gorm:"foreignKey:PhotoId" json:"comments,omitempty"`
-	LikedBy      []User     `gorm:"many2many:user_likes_photos" json:"liked_by,omitempty"`
-	Tags         []Tag      `gorm:"many2many:photo_tags" json:"tags,omitempty"`
+	ID           string       `gorm:"primaryKey" json:"id"`
+	Caption      string       `json:"caption"`
+	MediaType    string       `gorm:"not null;default:image" json:"media_type"`
+	PhotoUrl     string       `gorm:"not null;index" json:"photo_url"`
+	PosterUrl    string       `gorm:"index" json:"poster_url"`
+	MediumUrl    string       `gorm:"index" json:"medium_url"`
+	ThumbnailUrl string       `gorm:"index" json:"thumbnail_url"`
+	Width        int          `json:"width"`
+	Height       int          `json:"height"`
+	Duration     float64      `json:"duration"`
+	UserId       uint         `json:"user_id"`
+	CreatedAt    *time.Time   `gorm:"index" json:"created_at"`
+	UpdatedAt    *time.Time   `json:"updated_at,omitempty"`
+	User         User         `gorm:"foreignKey:UserId" json:"-"`
+	TotalComment int64        `gorm:"-" json:"total_comment"`
+	Comments     []Comment    `gorm:"foreignKey:PhotoId" json:"comments,omitempty"`
+	Media        []PhotoMedia `gorm:"foreignKey:PhotoId;constraint:OnDelete:CASCADE" json:"media,omitempty"`
+	LikedBy      []User       `gorm:"many2many:user_likes_photos" json:"liked_by,omitempty"`
+	Tags         []Tag        `gorm:"many2many:photo_tags" json:"tags,omitempty"`
+
+	// placeholder yang ditampilkan client selama gambar cover dimuat
+	BlurHash      string `json:"blur_hash"`
+	DominantColor string `json:"dominant_color"`
+
+	Mentions []Mention `gorm:"foreignKey:PhotoId;constraint:OnDelete:CASCADE" json:"mentions,omitempty"`
 }
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// MaxPhotoMedia adalah jumlah slide maksimal dalam satu postingan carousel
+const MaxPhotoMedia = 10
+
+// tipe media menentukan cara client menampilkan slide
+const (
+	MediaTypeImage     = "image"
+	MediaTypeAnimation = "animation"
+	MediaTypeVideo     = "video"
+)
+
+// PhotoMedia adalah satu slide dari postingan, urutannya ditentukan oleh Position
+type PhotoMedia struct {
+	ID           string     `gorm:"primaryKey" json:"id"`
+	PhotoId      string     `gorm:"not null;index" json:"photo_id"`
+	Position     int        `gorm:"not null" json:"position"`
+	MediaType    string     `gorm:"not null;default:image" json:"media_type"`
+	PhotoUrl     string     `gorm:"not null;index" json:"photo_url"`
+	PosterUrl    string     `gorm:"index" json:"poster_url"`
+	MediumUrl    string     `gorm:"index" json:"medium_url"`
+	ThumbnailUrl string     `gorm:"index" json:"thumbnail_url"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     float64    `json:"duration"`
+	AltText      string     `json:"alt_text"`
+	CreatedAt    *time.Time `json:"created_at"`
+
+	// PerceptualHash disalin dari media supaya foto yang mirip bisa dicari tanpa join
+	PerceptualHash int64  `gorm:"not null;default:0" json:"-"`
+	BlurHash       string `json:"blur_hash"`
+	DominantColor  string `json:"dominant_color"`
+}
+
+// SimilarPhoto adalah foto yang salah satu slide-nya mirip, Distance adalah jarak hamming terkecil
+type SimilarPhoto struct {
+	PhotoId  string
+	Distance int
+}
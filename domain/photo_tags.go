@@ -1,8 +1,13 @@
 package domain
 
+import "time"
+
+// CreatedAt adalah waktu tag dipasang ke foto, dipakai untuk menghitung trending tag.
+// Baris lama diisi dari photos.created_at di config/migration.go
 type PhotoTags struct {
-	PhotoId string `json:"photo_id"`
-	TagId   uint   `json:"tag_id"`
+	PhotoId   string    `json:"photo_id"`
+	TagId     uint      `gorm:"index" json:"tag_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
 }
 
 func (PhotoTags) TableName() string {
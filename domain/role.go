@@ -0,0 +1,27 @@
+package domain
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+
+	return false
+}
+
+// Actor adalah user yang sedang login, diambil dari claim JWT
+type Actor struct {
+	Id   uint
+	Role string
+}
+
+// IsStaff bernilai true untuk moderator dan admin
+func (a Actor) IsStaff() bool {
+	return a.Role == RoleModerator || a.Role == RoleAdmin
+}
@@ -1,7 +1,66 @@
 package domain
 
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxTagLength adalah panjang maksimal nama tag dalam rune
+const MaxTagLength = 100
+
+var tagFolder = cases.Fold()
+
+// Unique index idx_tags_name dibuat di config/migration.go setelah tag lama dinormalisasi dan digabungkan
 type Tag struct {
-	ID    uint    `gorm:"primarykey" json:"id"`
-	Name  string  `json:"name"`
+	ID uint `gorm:"primarykey" json:"id"`
+	// index text_pattern_ops dipakai untuk pencarian prefix (LIKE 'abc%'), nama tag sudah dinormalisasi
+	Name  string  `gorm:"index:idx_tags_name_prefix,expression:name text_pattern_ops" json:"name"`
 	Photo []Photo `gorm:"many2many:photo_tags" json:"photo,omitempty"`
 }
+
+// TagUsage adalah tag beserta jumlah foto yang memakainya
+type TagUsage struct {
+	ID          uint
+	Name        string
+	TotalPhotos int64
+}
+
+// TrendingTag membandingkan jumlah foto yang diberi tag di window terakhir dengan window sebelumnya
+type TrendingTag struct {
+	ID             uint    `json:"id"`
+	Name           string  `json:"name"`
+	RecentPhotos   int64   `json:"recent_photos"`
+	PreviousPhotos int64   `json:"previous_photos"`
+	Growth         float64 `json:"growth"`
+}
+
+// NormalizeTag menyeragamkan nama tag supaya #Café, #CAFÉ dan #café (dengan combining accent)
+// menjadi tag yang sama: NFKC lalu unicode case folding. Nama yang tidak valid menghasilkan string kosong.
+// Dipakai juga oleh migrasi data di config, jadi tidak boleh bergantung pada package helpers
+func NormalizeTag(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "#")
+	name = norm.NFKC.String(tagFolder.String(norm.NFKC.String(name)))
+
+	length := 0
+	for _, r := range name {
+		if !IsTagRune(r) {
+			return ""
+		}
+		length++
+	}
+
+	if length == 0 || length > MaxTagLength {
+		return ""
+	}
+
+	return name
+}
+
+// IsTagRune menerima huruf, angka, underscore dan combining mark supaya aksara seperti devanagari tetap utuh
+func IsTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) || r == '_'
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "huruf besar dan tanda #", tag: " #GoLang ", want: "golang"},
+		{name: "accent precomposed", tag: "CAFÉ", want: "café"},
+		{name: "accent combining", tag: "café", want: "café"},
+		{name: "fullwidth", tag: "ｇｏ２０２６", want: "go2026"},
+		{name: "case folding eszett", tag: "Straße", want: "strasse"},
+		{name: "combining mark devanagari", tag: "हिन्दी", want: "हिन्दी"},
+		{name: "underscore", tag: "my_gram", want: "my_gram"},
+		{name: "tanda baca tidak valid", tag: "go-lang", want: ""},
+		{name: "spasi di tengah tidak valid", tag: "go lang", want: ""},
+		{name: "kosong", tag: "#", want: ""},
+		{name: "batas panjang", tag: strings.Repeat("a", MaxTagLength), want: strings.Repeat("a", MaxTagLength)},
+		{name: "terlalu panjang", tag: strings.Repeat("a", MaxTagLength+1), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTag(tt.tag); got != tt.want {
+				t.Fatalf("NormalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
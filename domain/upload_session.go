@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// UploadSession adalah state resumable upload yang disimpan di redis,
+// isi filenya ditulis bertahap ke folder sementara sampai Offset sama dengan Length
+type UploadSession struct {
+	Id        string    `json:"id"`
+	UserId    uint      `json:"user_id"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
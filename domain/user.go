@@ -9,6 +9,8 @@ type User struct {
 	Username            string     `gorm:"not null" json:"username"`
 	Email               string     `gorm:"not null" json:"email"`
 	Password            string     `gorm:"not null" json:"-"`
+	Role                string     `gorm:"not null;default:user" json:"role"`
+	SuspendedAt         *time.Time `json:"suspended_at,omitempty"`
 	EmailVerificationAt *time.Time `json:"-"`
 	CreatedAt           *time.Time `json:"-"`
 	UpdatedAt           *time.Time `json:"-"`
@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type AdminHandler interface {
+	GetUsersHandler(ctx *gin.Context)
+	SuspendUserHandler(ctx *gin.Context)
+	UnsuspendUserHandler(ctx *gin.Context)
+	PutUserRoleHandler(ctx *gin.Context)
+	DeleteUserHandler(ctx *gin.Context)
+}
+
+type adminHandlerImpl struct {
+	adminUsecase usecase.AdminUsecase
+	validate     *validator.Validate
+}
+
+// GetUsers godoc
+// @Summary Get all users
+// @Description Get all registered users, newest first. Moderator or admin only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of users per page"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]domain.User,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /admin/users [get]
+// GetUsersHandler implements AdminHandler
+func (h *adminHandlerImpl) GetUsersHandler(ctx *gin.Context) {
+	page, err := helpers.NewNumericPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetUsersHandler, NewNumericPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	users, pageInfo, err := h.adminUsecase.GetUsers(ctx.Request.Context(), page)
+	if err != nil {
+		log.Printf("[GetUsersHandler, GetUsers] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get users success"),
+		helpers.WithPayload(users),
+		helpers.WithPagination(pageInfo),
+	).Send(ctx)
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Suspend the user identified by the given id. Moderators can only suspend regular users
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 403 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /admin/users/{id}/suspend [put]
+// SuspendUserHandler implements AdminHandler
+func (h *adminHandlerImpl) SuspendUserHandler(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Printf("[SuspendUserHandler, Atoi] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(helpers.ErrUserNotFound.Error()),
+			helpers.WithError(helpers.ErrorUserNotFound),
+		).Send(ctx)
+		return
+	}
+
+	actor := helpers.GetActor(ctx.MustGet("userData").(jwt.MapClaims))
+
+	err = h.adminUsecase.SuspendUser(ctx.Request.Context(), uint(userId), actor)
+	if err != nil {
+		log.Printf("[SuspendUserHandler, SuspendUser] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("suspend user success"),
+	).Send(ctx)
+}
+
+// UnsuspendUser godoc
+// @Summary Unsuspend user
+// @Description Lift the suspension of the user identified by the given id
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 403 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /admin/users/{id}/suspend [delete]
+// UnsuspendUserHandler implements AdminHandler
+func (h *adminHandlerImpl) UnsuspendUserHandler(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Printf("[UnsuspendUserHandler, Atoi] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(helpers.ErrUserNotFound.Error()),
+			helpers.WithError(helpers.ErrorUserNotFound),
+		).Send(ctx)
+		return
+	}
+
+	actor := helpers.GetActor(ctx.MustGet("userData").(jwt.MapClaims))
+
+	err = h.adminUsecase.UnsuspendUser(ctx.Request.Context(), uint(userId), actor)
+	if err != nil {
+		log.Printf("[UnsuspendUserHandler, UnsuspendUser] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("unsuspend user success"),
+	).Send(ctx)
+}
+
+// PutUserRole godoc
+// @Summary Change user role
+// @Description Change the role of the user identified by the given id. Admin only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Param role body request.ChangeRoleRequest true "new role"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /admin/users/{id}/role [put]
+// PutUserRoleHandler implements AdminHandler
+func (h *adminHandlerImpl) PutUserRoleHandler(ctx *gin.Context) {
+	var payload request.ChangeRoleRequest
+
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Printf("[PutUserRoleHandler, Atoi] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(helpers.ErrUserNotFound.Error()),
+			helpers.WithError(helpers.ErrorUserNotFound),
+		).Send(ctx)
+		return
+	}
+
+	err = ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		log.Printf("[PutUserRoleHandler, ShouldBindJSON] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(helpers.ErrorGeneral),
+			helpers.WithHttpCode(http.StatusBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	err = h.validate.Struct(payload)
+	if err != nil {
+		log.Printf("[PutUserRoleHandler, Struct] with error detail %v", err.Error())
+		errorMessage := helpers.FormatValidationErrors(err)
+
+		myErr, ok := helpers.ErrorMapping[errorMessage.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(errorMessage.Error()),
+			helpers.WithError(myErr),
+			helpers.WithHttpCode(http.StatusBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	actor := helpers.GetActor(ctx.MustGet("userData").(jwt.MapClaims))
+
+	err = h.adminUsecase.ChangeRole(ctx.Request.Context(), uint(userId), payload.Role, actor)
+	if err != nil {
+		log.Printf("[PutUserRoleHandler, ChangeRole] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("change user role success"),
+	).Send(ctx)
+}
+
+// DeleteUser godoc
+// @Summary Delete user
+// @Description Delete the user identified by the given id together with their photos, comments, likes and follows. Admin only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the user"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 403 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /admin/users/{id} [delete]
+// DeleteUserHandler implements AdminHandler
+func (h *adminHandlerImpl) DeleteUserHandler(ctx *gin.Context) {
+	userId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		log.Printf("[DeleteUserHandler, Atoi] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(helpers.ErrUserNotFound.Error()),
+			helpers.WithError(helpers.ErrorUserNotFound),
+		).Send(ctx)
+		return
+	}
+
+	actor := helpers.GetActor(ctx.MustGet("userData").(jwt.MapClaims))
+
+	err = h.adminUsecase.DeleteUser(ctx.Request.Context(), uint(userId), actor)
+	if err != nil {
+		log.Printf("[DeleteUserHandler, DeleteUser] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("delete user success"),
+	).Send(ctx)
+}
+
+func NewAdminHandlerImpl(adminUsecase usecase.AdminUsecase, validate *validator.Validate) AdminHandler {
+	return &adminHandlerImpl{adminUsecase: adminUsecase, validate: validate}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
@@ -20,11 +21,14 @@ type AuthHandler interface {
 	LogoutHandler(ctx *gin.Context)
 	VerifyEmail(ctx *gin.Context)
 	ResendEmail(ctx *gin.Context)
+	ForgotPasswordHandler(ctx *gin.Context)
+	ResetPasswordHandler(ctx *gin.Context)
 }
 
 type authHandler struct {
-	authUsecase usecase.AuthenticationUsecase
-	validate    *validator.Validate
+	authUsecase          usecase.AuthenticationUsecase
+	tokenDenylistUsecase usecase.TokenDenylistUsecase
+	validate             *validator.Validate
 }
 
 // ResendEmail implements AuthHandler.
@@ -91,7 +95,7 @@ func (h *authHandler) VerifyEmail(ctx *gin.Context) {
 
 // PutAccessTokenHandler implements AuthHandler.
 func (h *authHandler) PutAccessTokenHandler(ctx *gin.Context) {
-	var payload domain.Authentication
+	var payload request.RefreshTokenRequest
 
 	err := ctx.ShouldBindJSON(&payload)
 	if err != nil {
@@ -104,12 +108,10 @@ func (h *authHandler) PutAccessTokenHandler(ctx *gin.Context) {
 		return
 	}
 
-	err = h.authUsecase.ExistsByRefreshToken(ctx.Request.Context(), payload.RefreshToken)
-	if err != nil {
-		return
-	}
+	payload.UserAgent = ctx.Request.UserAgent()
+	payload.IpAddress = ctx.ClientIP()
 
-	claims, err := helpers.VerifyRefreshToken(payload.RefreshToken)
+	tokens, err := h.authUsecase.RefreshAccessToken(ctx.Request.Context(), payload)
 	if err != nil {
 		myErr, ok := helpers.ErrorMapping[err.Error()]
 
@@ -125,13 +127,12 @@ func (h *authHandler) PutAccessTokenHandler(ctx *gin.Context) {
 		return
 	}
 
-	accessToken := helpers.NewAccessToken(claims.Id).GenerateAccessToken()
-
 	helpers.NewResponse(
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("this is your new access token"),
 		helpers.WithPayload(gin.H{
-			"access_token": accessToken,
+			"access_token":  tokens.AccessToken,
+			"refresh_token": tokens.RefreshToken,
 		}),
 	).Send(ctx)
 
@@ -152,8 +153,37 @@ func (h *authHandler) LogoutHandler(ctx *gin.Context) {
 		return
 	}
 
+	// access token dicabut lebih dulu supaya tetap mati walaupun refresh token-nya sudah tidak ada
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	err = h.tokenDenylistUsecase.RevokeToken(ctx.Request.Context(), userData)
+	if err != nil {
+		log.Printf("[LogoutHandler, RevokeToken] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
 	err = h.authUsecase.Delete(ctx.Request.Context(), payload.RefreshToken)
 	if err != nil {
+		log.Printf("[LogoutHandler, Delete] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
 		return
 	}
 
@@ -208,6 +238,9 @@ func (h *authHandler) PostUserLoginHandler(ctx *gin.Context) {
 		return
 	}
 
+	payload.UserAgent = ctx.Request.UserAgent()
+	payload.IpAddress = ctx.ClientIP()
+
 	loginResponse, err := h.authUsecase.Login(ctx.Request.Context(), payload)
 	if err != nil {
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -223,11 +256,6 @@ func (h *authHandler) PostUserLoginHandler(ctx *gin.Context) {
 		return
 	}
 
-	err = h.authUsecase.Add(ctx.Request.Context(), loginResponse.RefreshToken)
-	if err != nil {
-		return
-	}
-
 	helpers.NewResponse(
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("login success"),
@@ -303,9 +331,142 @@ func (h *authHandler) PostUserRegisterHandler(ctx *gin.Context) {
 	).Send(ctx)
 }
 
-func NewAuthHandler(authUsecase usecase.AuthenticationUsecase, validate *validator.Validate) AuthHandler {
+// ForgotPassword godoc
+// @Summary Forgot password
+// @Description Send a one-time password reset code to the given email
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param email body request.ForgotPasswordRequest true "registered email"
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /password/forgot [post]
+// ForgotPasswordHandler implements AuthHandler
+func (h *authHandler) ForgotPasswordHandler(ctx *gin.Context) {
+	var payload request.ForgotPasswordRequest
+
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		myErr := helpers.ErrorGeneral
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+			helpers.WithHttpCode(http.StatusInternalServerError),
+		).Send(ctx)
+		return
+	}
+
+	err = h.validate.Struct(payload)
+	if err != nil {
+		errorMessage := helpers.FormatValidationErrors(err)
+
+		myErr, ok := helpers.ErrorMapping[errorMessage.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(errorMessage.Error()),
+			helpers.WithError(myErr),
+			helpers.WithHttpCode(http.StatusBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	err = h.authUsecase.ForgotPassword(ctx.Request.Context(), payload.Email)
+	if err != nil {
+		log.Printf("[ForgotPasswordHandler, ForgotPassword] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("if the email is registered, a reset code has been sent"),
+	).Send(ctx)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Reset the password using the code sent by email. All refresh tokens of the user are revoked
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param reset body request.ResetPasswordRequest true "reset code and new password"
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /password/reset [post]
+// ResetPasswordHandler implements AuthHandler
+func (h *authHandler) ResetPasswordHandler(ctx *gin.Context) {
+	var payload request.ResetPasswordRequest
+
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		myErr := helpers.ErrorGeneral
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+			helpers.WithHttpCode(http.StatusInternalServerError),
+		).Send(ctx)
+		return
+	}
+
+	err = h.validate.Struct(payload)
+	if err != nil {
+		errorMessage := helpers.FormatValidationErrors(err)
+
+		myErr, ok := helpers.ErrorMapping[errorMessage.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(errorMessage.Error()),
+			helpers.WithError(myErr),
+			helpers.WithHttpCode(http.StatusBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	err = h.authUsecase.ResetPassword(ctx.Request.Context(), payload)
+	if err != nil {
+		log.Printf("[ResetPasswordHandler, ResetPassword] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("reset password success"),
+	).Send(ctx)
+}
+
+func NewAuthHandler(authUsecase usecase.AuthenticationUsecase, tokenDenylistUsecase usecase.TokenDenylistUsecase, validate *validator.Validate) AuthHandler {
 	return &authHandler{
-		authUsecase: authUsecase,
-		validate:    validate,
+		authUsecase:          authUsecase,
+		tokenDenylistUsecase: tokenDenylistUsecase,
+		validate:             validate,
 	}
 }
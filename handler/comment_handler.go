@@ -18,6 +18,8 @@ type CommentHandler interface {
 	PostCommentHandler(ctx *gin.Context)
 	GetCommentsHandler(ctx *gin.Context)
 	GetCommentHandler(ctx *gin.Context)
+	GetRepliesHandler(ctx *gin.Context)
+	PostCommentLikesHandler(ctx *gin.Context)
 	PutCommentHandler(ctx *gin.Context)
 	DeleteCommentHandler(ctx *gin.Context)
 }
@@ -46,7 +48,25 @@ func (h *commentHandler) DeleteCommentHandler(ctx *gin.Context) {
 	requestParam := ctx.Param("commentId")
 	commentId, _ := strconv.Atoi(requestParam)
 
-	h.commentUsecase.Delete(ctx.Request.Context(), uint(commentId), photoId)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	actor := helpers.GetActor(userData)
+
+	err := h.commentUsecase.Delete(ctx.Request.Context(), uint(commentId), photoId, actor)
+	if err != nil {
+		log.Printf("[DeleteCommentHandler, Delete] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
 	helpers.NewResponse(
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("delete comment success"),
@@ -71,7 +91,10 @@ func (h *commentHandler) GetCommentHandler(ctx *gin.Context) {
 	requestParam := ctx.Param("commentId")
 	commentId, _ := strconv.Atoi(requestParam)
 
-	comment, err := h.commentUsecase.GetById(ctx.Request.Context(), uint(commentId), photoId)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	comment, err := h.commentUsecase.GetById(ctx.Request.Context(), uint(commentId), photoId, userID)
 	if err != nil {
 		log.Printf("[GetCommentHandler, GetById] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -101,6 +124,9 @@ func (h *commentHandler) GetCommentHandler(ctx *gin.Context) {
 // @Tags comment
 // @Accept json
 // @Produce json
+// @Param sort query string false "newest (default) or top"
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of comments per page"
 // @Security JWT
 // @Success 200 {object} helpers.SuccessResult{data=domain.Comment,code=int,message=string}
 // @Failure 400 {object} helpers.BadRequest{code=int,message=string}
@@ -110,7 +136,26 @@ func (h *commentHandler) GetCommentHandler(ctx *gin.Context) {
 func (h *commentHandler) GetCommentsHandler(ctx *gin.Context) {
 	photoId := ctx.Param("id")
 
-	comments, err := h.commentUsecase.GetAllCommentsByPhotoId(ctx.Request.Context(), photoId)
+	page, err := helpers.NewNumericPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetCommentsHandler, NewNumericPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	comments, pageInfo, err := h.commentUsecase.GetAllCommentsByPhotoId(ctx.Request.Context(), photoId, userID, ctx.Query("sort"), page)
 
 	if err != nil {
 		log.Printf("[GetCommentsHandler, GetAllCommentsByPhotoId] with error detail %v", err.Error())
@@ -132,6 +177,115 @@ func (h *commentHandler) GetCommentsHandler(ctx *gin.Context) {
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("get all comments success"),
 		helpers.WithPayload(comments),
+		helpers.WithPagination(pageInfo),
+	).Send(ctx)
+}
+
+// GetReplies godoc
+// @Summary Get replies of a comment
+// @Description Get replies of the top level comment corresponding to the input commentId
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the photo"
+// @Param commentId path int true "ID of the parent comment"
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of replies per page"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]domain.Comment,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /photos/{id}/comments/{commentId}/replies [get]
+// GetRepliesHandler implements CommentHandler
+func (h *commentHandler) GetRepliesHandler(ctx *gin.Context) {
+	photoId := ctx.Param("id")
+	requestParam := ctx.Param("commentId")
+	commentId, _ := strconv.Atoi(requestParam)
+
+	page, err := helpers.NewNumericPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetRepliesHandler, NewNumericPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	replies, pageInfo, err := h.commentUsecase.GetReplies(ctx.Request.Context(), photoId, uint(commentId), userID, page)
+	if err != nil {
+		log.Printf("[GetRepliesHandler, GetReplies] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get replies success"),
+		helpers.WithPayload(replies),
+		helpers.WithPagination(pageInfo),
+	).Send(ctx)
+}
+
+// LikeComment godoc
+// @Summary like comment
+// @Description user can like the comment, calling it again removes the like
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the photo"
+// @Param commentId path int true "ID of the comment"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=string,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /photos/{id}/comments/{commentId}/likes [post]
+// PostCommentLikesHandler implements CommentHandler
+func (h *commentHandler) PostCommentLikesHandler(ctx *gin.Context) {
+	photoId := ctx.Param("id")
+	requestParam := ctx.Param("commentId")
+	commentId, _ := strconv.Atoi(requestParam)
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	likes, err := h.commentUsecase.LikeTheComment(ctx.Request.Context(), photoId, uint(commentId), userID)
+	if err != nil {
+		log.Printf("[PostCommentLikesHandler, LikeTheComment] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage(likes),
 	).Send(ctx)
 }
 
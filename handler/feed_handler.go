@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler interface {
+	GetFeedHandler(ctx *gin.Context)
+}
+
+type feedHandlerImpl struct {
+	feedUsecase usecase.FeedUsecase
+}
+
+// GetFeed godoc
+// @Summary Get home feed
+// @Description Get photos from users followed by the logged in user, newest first
+// @Tags feed
+// @Accept json
+// @Produce json
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of photos per page"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]response.PhotoResponse,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /me/feed [get]
+// GetFeedHandler implements FeedHandler
+func (h *feedHandlerImpl) GetFeedHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	page, err := helpers.NewPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetFeedHandler, NewPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	feed, pageInfo, err := h.feedUsecase.GetHomeFeed(ctx.Request.Context(), userID, page)
+	if err != nil {
+		log.Printf("[GetFeedHandler, GetHomeFeed] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get feed success"),
+		helpers.WithPayload(feed),
+		helpers.WithPagination(pageInfo),
+	).Send(ctx)
+}
+
+func NewFeedHandlerImpl(feedUsecase usecase.FeedUsecase) FeedHandler {
+	return &feedHandlerImpl{feedUsecase: feedUsecase}
+}
@@ -25,7 +25,23 @@ type followHandlerImpl struct {
 func (h *followHandlerImpl) GetFollowersHandler(ctx *gin.Context) {
 	username := ctx.Param("username")
 
-	followers, err := h.followUsecase.GetFollowersByUsername(ctx.Request.Context(), username)
+	page, err := helpers.NewNumericPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetFollowersHandler, NewNumericPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	followers, pageInfo, err := h.followUsecase.GetFollowersByUsername(ctx.Request.Context(), username, page)
 	if err != nil {
 		log.Printf("[GetFollowersHandler, GetFollowersByUsername] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -45,13 +61,30 @@ func (h *followHandlerImpl) GetFollowersHandler(ctx *gin.Context) {
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("get follower success"),
 		helpers.WithPayload(followers),
+		helpers.WithPagination(pageInfo),
 	).Send(ctx)
 }
 
 func (h *followHandlerImpl) GetFollowingsHandler(ctx *gin.Context) {
 	username := ctx.Param("username")
 
-	followings, err := h.followUsecase.GetFollowingsByUsername(ctx.Request.Context(), username)
+	page, err := helpers.NewNumericPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetFollowingsHandler, NewNumericPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	followings, pageInfo, err := h.followUsecase.GetFollowingsByUsername(ctx.Request.Context(), username, page)
 	if err != nil {
 		log.Printf("[GetFollowingsHandler, GetFollowingsByUsername] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -71,6 +104,7 @@ func (h *followHandlerImpl) GetFollowingsHandler(ctx *gin.Context) {
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("get following uccess"),
 		helpers.WithPayload(followings),
+		helpers.WithPagination(pageInfo),
 	).Send(ctx)
 }
 
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type JwksHandler interface {
+	GetJwksHandler(ctx *gin.Context)
+}
+
+type jwksHandlerImpl struct{}
+
+// GetJwks godoc
+// @Summary Get JSON Web Key Set
+// @Description Public keys used to verify access tokens. Responds with a plain JWKS document instead of the usual envelope
+// @Tags auth
+// @Produce json
+// @Success 200 {object} object{keys=[]helpers.JSONWebKey}
+// @Router /.well-known/jwks.json [get]
+// GetJwksHandler implements JwksHandler
+func (h *jwksHandlerImpl) GetJwksHandler(ctx *gin.Context) {
+	// formatnya mengikuti RFC 7517 supaya bisa langsung dibaca library jwt di service lain
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, gin.H{
+		"keys": helpers.JSONWebKeys(),
+	})
+}
+
+func NewJwksHandlerImpl() JwksHandler {
+	return &jwksHandlerImpl{}
+}
@@ -21,6 +21,7 @@ type PhotoHandler interface {
 	GetPhotoHandler(ctx *gin.Context)
 	PutPhotoHandler(ctx *gin.Context)
 	DeletePhotoHandler(ctx *gin.Context)
+	GetSimilarPhotosHandler(ctx *gin.Context)
 }
 
 type photoHandler struct {
@@ -45,7 +46,10 @@ func (h *photoHandler) DeletePhotoHandler(ctx *gin.Context) {
 
 	photoId := ctx.Param("id")
 
-	err := h.photoUsecase.Delete(ctx.Request.Context(), photoId)
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	actor := helpers.GetActor(userData)
+
+	err := h.photoUsecase.Delete(ctx.Request.Context(), photoId, actor)
 	if err != nil {
 		log.Printf("[DeletePhotoHandler, DeleteById] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -112,7 +116,8 @@ func (h *photoHandler) GetPhotoHandler(ctx *gin.Context) {
 // @Tags photo
 // @Accept json
 // @Produce json
-// @Param id path int true "ID of the photo"
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of photos per page"
 // @Security JWT
 // @Success 200 {object} helpers.SuccessResult{data=domain.Photo,code=int,message=string}
 // @Failure 400 {object} helpers.BadRequest{code=int,message=string}
@@ -120,7 +125,23 @@ func (h *photoHandler) GetPhotoHandler(ctx *gin.Context) {
 // @Router /photo [get]
 // GetPhotosHandler implements PhotoHandler
 func (h *photoHandler) GetPhotosHandler(ctx *gin.Context) {
-	photos, err := h.photoUsecase.GetAll(ctx.Request.Context())
+	page, err := helpers.NewPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetPhotosHandler, NewPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	photos, pageInfo, err := h.photoUsecase.GetAll(ctx.Request.Context(), page)
 	if err != nil {
 		log.Printf("[GetPhotosHandler, GetAll] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -140,6 +161,7 @@ func (h *photoHandler) GetPhotosHandler(ctx *gin.Context) {
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("get all photos success"),
 		helpers.WithPayload(photos),
+		helpers.WithPagination(pageInfo),
 	).Send(ctx)
 }
 
@@ -160,7 +182,23 @@ func (h *photoHandler) GetPhotosByUserIdHandler(ctx *gin.Context) {
 	userData := ctx.MustGet("userData").(jwt.MapClaims)
 	userID := uint(userData["Id"].(float64))
 
-	photo, err := h.photoUsecase.GetAllPhotosByUserId(ctx.Request.Context(), userID)
+	page, err := helpers.NewPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetPhotosByUserIdHandler, NewPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	photo, pageInfo, err := h.photoUsecase.GetAllPhotosByUserId(ctx.Request.Context(), userID, page)
 	if err != nil {
 		log.Printf("[GetPhotosHandler, GetAll] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
@@ -180,6 +218,7 @@ func (h *photoHandler) GetPhotosByUserIdHandler(ctx *gin.Context) {
 		helpers.WithHttpCode(http.StatusOK),
 		helpers.WithMessage("get all photos success"),
 		helpers.WithPayload(photo),
+		helpers.WithPagination(pageInfo),
 	).Send(ctx)
 
 }
@@ -335,6 +374,33 @@ func (h *photoHandler) PutPhotoHandler(ctx *gin.Context) {
 
 }
 
+// GetSimilarPhotosHandler implements PhotoHandler
+func (h *photoHandler) GetSimilarPhotosHandler(ctx *gin.Context) {
+	photoId := ctx.Param("id")
+
+	photos, err := h.photoUsecase.GetSimilar(ctx.Request.Context(), photoId)
+	if err != nil {
+		log.Printf("[GetSimilarPhotosHandler, GetSimilar] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get similar photos success"),
+		helpers.WithPayload(photos),
+	).Send(ctx)
+}
+
 func NewPhotoHandler(photoUsecase usecase.PhotoUsecase, validate *validator.Validate) PhotoHandler {
 	return &photoHandler{photoUsecase: photoUsecase, validate: validate}
 }
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler interface {
+	GetSessionsHandler(ctx *gin.Context)
+	DeleteSessionHandler(ctx *gin.Context)
+	DeleteSessionsHandler(ctx *gin.Context)
+}
+
+type sessionHandlerImpl struct {
+	authUsecase usecase.AuthenticationUsecase
+}
+
+// GetSessions godoc
+// @Summary Get my sessions
+// @Description Get every device the logged in user is signed in on
+// @Tags session
+// @Accept json
+// @Produce json
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]response.SessionResponse,code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /me/sessions [get]
+// GetSessionsHandler implements SessionHandler
+func (h *sessionHandlerImpl) GetSessionsHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+	currentSessionId := helpers.GetSessionId(userData)
+
+	sessions, err := h.authUsecase.GetSessions(ctx.Request.Context(), userID, currentSessionId)
+	if err != nil {
+		log.Printf("[GetSessionsHandler, GetSessions] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get sessions success"),
+		helpers.WithPayload(sessions),
+	).Send(ctx)
+}
+
+// DeleteSession godoc
+// @Summary Revoke a session
+// @Description Sign out the device identified by the given session id
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the session"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /me/sessions/{id} [delete]
+// DeleteSessionHandler implements SessionHandler
+func (h *sessionHandlerImpl) DeleteSessionHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	err := h.authUsecase.RevokeSession(ctx.Request.Context(), userID, ctx.Param("id"))
+	if err != nil {
+		log.Printf("[DeleteSessionHandler, RevokeSession] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("revoke session success"),
+	).Send(ctx)
+}
+
+// DeleteSessions godoc
+// @Summary Sign out everywhere
+// @Description Revoke every session of the logged in user, including the current one
+// @Tags session
+// @Accept json
+// @Produce json
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /me/sessions [delete]
+// DeleteSessionsHandler implements SessionHandler
+func (h *sessionHandlerImpl) DeleteSessionsHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	err := h.authUsecase.RevokeAllSessions(ctx.Request.Context(), userID)
+	if err != nil {
+		log.Printf("[DeleteSessionsHandler, RevokeAllSessions] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("sign out from all sessions success"),
+	).Send(ctx)
+}
+
+func NewSessionHandlerImpl(authUsecase usecase.AuthenticationUsecase) SessionHandler {
+	return &sessionHandlerImpl{authUsecase: authUsecase}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler interface {
+	GetTagHandler(ctx *gin.Context)
+	GetTagPhotosHandler(ctx *gin.Context)
+	SearchTagsHandler(ctx *gin.Context)
+	GetTrendingTagsHandler(ctx *gin.Context)
+}
+
+type tagHandlerImpl struct {
+	tagUsecase usecase.TagUsecase
+}
+
+// GetTag godoc
+// @Summary Get tag
+// @Description Get a tag and the number of photos using it, the name is matched case insensitively.
+// @Description Tags named search or trending are requested with the # prefix (/tags/%23search) because the plain path is taken by those endpoints
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param name path string true "name of the tag, with or without #"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=response.TagResponse,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /tags/{name} [get]
+// GetTagHandler implements TagHandler
+func (h *tagHandlerImpl) GetTagHandler(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	tag, err := h.tagUsecase.GetByName(ctx.Request.Context(), name)
+	if err != nil {
+		log.Printf("[GetTagHandler, GetByName] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get tag success"),
+		helpers.WithPayload(tag),
+	).Send(ctx)
+}
+
+// GetTagPhotos godoc
+// @Summary Get photos by tag
+// @Description Get photos using the tag, newest first
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param name path string true "name of the tag, with or without #"
+// @Param cursor query string false "cursor from the previous page"
+// @Param limit query int false "number of photos per page"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]domain.Photo,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /tags/{name}/photos [get]
+// GetTagPhotosHandler implements TagHandler
+func (h *tagHandlerImpl) GetTagPhotosHandler(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	page, err := helpers.NewPageRequest(ctx.Query("cursor"), ctx.Query("limit"))
+	if err != nil {
+		log.Printf("[GetTagPhotosHandler, NewPageRequest] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	photos, pageInfo, err := h.tagUsecase.GetPhotosByName(ctx.Request.Context(), name, page)
+	if err != nil {
+		log.Printf("[GetTagPhotosHandler, GetPhotosByName] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get tag photos success"),
+		helpers.WithPayload(photos),
+		helpers.WithPagination(pageInfo),
+	).Send(ctx)
+}
+
+// SearchTags godoc
+// @Summary Search tags
+// @Description Autocomplete tags starting with q, the most used tags first
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param q query string true "prefix of the tag name, with or without #"
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]response.TagResponse,code=int,message=string}
+// @Failure 400 {object} helpers.BadRequest{code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /tags/search [get]
+// SearchTagsHandler implements TagHandler
+func (h *tagHandlerImpl) SearchTagsHandler(ctx *gin.Context) {
+	tags, err := h.tagUsecase.Search(ctx.Request.Context(), ctx.Query("q"))
+	if err != nil {
+		log.Printf("[SearchTagsHandler, Search] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("search tags success"),
+		helpers.WithPayload(tags),
+	).Send(ctx)
+}
+
+// GetTrendingTags godoc
+// @Summary Get trending tags
+// @Description Get tags whose usage grew the fastest in the latest window compared to the window before
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Security JWT
+// @Success 200 {object} helpers.SuccessResult{data=[]domain.TrendingTag,code=int,message=string}
+// @Success 500 {object} helpers.InternalServerError{code=int,message=string}
+// @Router /tags/trending [get]
+// GetTrendingTagsHandler implements TagHandler
+func (h *tagHandlerImpl) GetTrendingTagsHandler(ctx *gin.Context) {
+	tags, err := h.tagUsecase.GetTrending(ctx.Request.Context())
+	if err != nil {
+		log.Printf("[GetTrendingTagsHandler, GetTrending] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("get trending tags success"),
+		helpers.WithPayload(tags),
+	).Send(ctx)
+}
+
+func NewTagHandlerImpl(tagUsecase usecase.TagUsecase) TagHandler {
+	return &tagHandlerImpl{tagUsecase: tagUsecase}
+}
@@ -1,14 +1,24 @@
 package handler
 
 import (
+	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/request"
 	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/usecase"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
+// versi protokol tus yang diikuti resumable upload
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination"
+)
+
 type UploadFileHandler struct {
 	uploadFileUsecase usecase.UploadFileUsecase
 }
@@ -33,8 +43,148 @@ func (h *UploadFileHandler) UploadFileHandler(ctx *gin.Context) {
 		return
 	}
 
-	url, err := h.uploadFileUsecase.Upload(ctx.Request.Context(), file, userID)
+	uploadedFile, err := h.uploadFileUsecase.Upload(ctx.Request.Context(), file, userID)
+	if err != nil {
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusCreated),
+		helpers.WithMessage("berhasil upload file"),
+		helpers.WithPayload(uploadedFile),
+	).Send(ctx)
+}
+
+// OptionsUploadHandler memberi tahu client tus versi dan extension yang didukung
+func (h *UploadFileHandler) OptionsUploadHandler(ctx *gin.Context) {
+	ctx.Header("Tus-Resumable", tusVersion)
+	ctx.Header("Tus-Version", tusVersion)
+	ctx.Header("Tus-Extension", tusExtensions)
+	ctx.Header("Tus-Max-Size", strconv.FormatInt(helpers.MaxUploadSize(), 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// PostUploadSessionHandler membuat session resumable upload, ukuran file dikirim lewat header Upload-Length
+func (h *UploadFileHandler) PostUploadSessionHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	ctx.Header("Tus-Resumable", tusVersion)
+
+	length, err := strconv.ParseInt(ctx.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		err = helpers.ErrUploadLengthInvalid
+	}
+
+	var session *domain.UploadSession
+	if err == nil {
+		session, err = h.uploadFileUsecase.CreateSession(ctx.Request.Context(), userID, length)
+	}
+	if err != nil {
+		log.Printf("[PostUploadSessionHandler, CreateSession] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	ctx.Header("Location", ctx.Request.URL.Path+"/"+session.Id)
+	ctx.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusCreated),
+		helpers.WithMessage("upload session created"),
+		helpers.WithPayload(session),
+	).Send(ctx)
+}
+
+// HeadUploadSessionHandler mengembalikan offset terakhir supaya client tahu harus lanjut dari mana
+func (h *UploadFileHandler) HeadUploadSessionHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	ctx.Header("Tus-Resumable", tusVersion)
+	ctx.Header("Cache-Control", "no-store")
+
+	session, err := h.uploadFileUsecase.GetSession(ctx.Request.Context(), ctx.Param("id"), userID)
+	if err != nil {
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		ctx.Status(myErr.HttpCode)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	ctx.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// PatchUploadSessionHandler menulis body request sebagai chunk mulai dari header Upload-Offset.
+// Dipasang untuk PATCH (tus) dan PUT
+func (h *UploadFileHandler) PatchUploadSessionHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	ctx.Header("Tus-Resumable", tusVersion)
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		err = helpers.ErrUploadOffsetInvalid
+	}
+
+	var session *domain.UploadSession
+	if err == nil {
+		session, err = h.uploadFileUsecase.WriteChunk(ctx.Request.Context(), ctx.Param("id"), userID, offset, ctx.Request.Body)
+	}
+	if session != nil {
+		ctx.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	}
+	if err != nil {
+		log.Printf("[PatchUploadSessionHandler, WriteChunk] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PostFinalizeUploadHandler menggabungkan chunk lalu menjalankan validasi dan penyimpanan yang sama dengan upload biasa
+func (h *UploadFileHandler) PostFinalizeUploadHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	uploadedFile, err := h.uploadFileUsecase.FinalizeSession(ctx.Request.Context(), ctx.Param("id"), userID)
 	if err != nil {
+		log.Printf("[PostFinalizeUploadHandler, FinalizeSession] with error detail %v", err.Error())
 		myErr, ok := helpers.ErrorMapping[err.Error()]
 
 		if !ok {
@@ -51,6 +201,112 @@ func (h *UploadFileHandler) UploadFileHandler(ctx *gin.Context) {
 	helpers.NewResponse(
 		helpers.WithHttpCode(http.StatusCreated),
 		helpers.WithMessage("berhasil upload file"),
-		helpers.WithPayload(url),
+		helpers.WithPayload(uploadedFile),
+	).Send(ctx)
+}
+
+// DeleteUploadSessionHandler membatalkan resumable upload beserta chunk yang sudah terkirim
+func (h *UploadFileHandler) DeleteUploadSessionHandler(ctx *gin.Context) {
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	ctx.Header("Tus-Resumable", tusVersion)
+
+	err := h.uploadFileUsecase.DeleteSession(ctx.Request.Context(), ctx.Param("id"), userID)
+	if err != nil {
+		log.Printf("[DeleteUploadSessionHandler, DeleteSession] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PostPresignedUploadHandler membuat form presigned supaya client bisa upload langsung ke storage
+func (h *UploadFileHandler) PostPresignedUploadHandler(ctx *gin.Context) {
+	var payload request.PresignUploadRequest
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		log.Printf("[PostPresignedUploadHandler, ShouldBindJSON] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(helpers.ErrorBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	presigned, err := h.uploadFileUsecase.PresignUpload(ctx.Request.Context(), payload, userID)
+	if err != nil {
+		log.Printf("[PostPresignedUploadHandler, PresignUpload] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusCreated),
+		helpers.WithMessage("presigned upload created"),
+		helpers.WithPayload(presigned),
+	).Send(ctx)
+}
+
+// PostConfirmUploadHandler memproses file hasil presigned upload, url di response menggantikan file_url dari presign
+func (h *UploadFileHandler) PostConfirmUploadHandler(ctx *gin.Context) {
+	var payload request.ConfirmUploadRequest
+
+	userData := ctx.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["Id"].(float64))
+
+	err := ctx.ShouldBindJSON(&payload)
+	if err != nil {
+		log.Printf("[PostConfirmUploadHandler, ShouldBindJSON] with error detail %v", err.Error())
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(helpers.ErrorBadRequest),
+		).Send(ctx)
+		return
+	}
+
+	uploadedFile, err := h.uploadFileUsecase.ConfirmUpload(ctx.Request.Context(), payload, userID)
+	if err != nil {
+		log.Printf("[PostConfirmUploadHandler, ConfirmUpload] with error detail %v", err.Error())
+		myErr, ok := helpers.ErrorMapping[err.Error()]
+
+		if !ok {
+			myErr = helpers.ErrorGeneral
+		}
+
+		helpers.NewResponse(
+			helpers.WithMessage(err.Error()),
+			helpers.WithError(myErr),
+		).Send(ctx)
+		return
+	}
+
+	helpers.NewResponse(
+		helpers.WithHttpCode(http.StatusOK),
+		helpers.WithMessage("berhasil upload file"),
+		helpers.WithPayload(uploadedFile),
 	).Send(ctx)
 }
@@ -0,0 +1,179 @@
+package helpers
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// gambar dikecilkan dulu, blurhash dan warna dominan tidak butuh detail
+	placeholderDimension = 32
+
+	blurHashCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+)
+
+// Placeholder menghitung blurhash dan warna dominan (#rrggbb) yang dipakai client
+// sebagai pengganti gambar selama gambar aslinya belum selesai dimuat
+func Placeholder(img image.Image) (blurHash string, dominantColor string) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", ""
+	}
+
+	if width >= height {
+		height = max(1, height*placeholderDimension/width)
+		width = placeholderDimension
+	} else {
+		width = max(1, width*placeholderDimension/height)
+		height = placeholderDimension
+	}
+
+	small := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.BiLinear.Scale(small, small.Bounds(), img, bounds, draw.Src, nil)
+
+	// jumlah komponen mengikuti orientasi gambar, 4x3 untuk landscape dan 3x4 untuk portrait
+	xComponents, yComponents := 4, 3
+	if height > width {
+		xComponents, yComponents = 3, 4
+	}
+
+	return encodeBlurHash(small, xComponents, yComponents), dominantColorOf(small)
+}
+
+// encodeBlurHash mengikuti algoritma referensi https://github.com/woltapp/blurhash
+func encodeBlurHash(img *image.NRGBA, xComponents, yComponents int) string {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i*x)/float64(width)) * math.Cos(math.Pi*float64(j*y)/float64(height))
+					pixel := img.NRGBAAt(x, y)
+					factor[0] += basis * srgbToLinear(pixel.R)
+					factor[1] += basis * srgbToLinear(pixel.G)
+					factor[2] += basis * srgbToLinear(pixel.B)
+				}
+			}
+
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	hash := strings.Builder{}
+	hash.WriteString(encodeBase83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc, ac := factors[0], factors[1:]
+
+	maximumValue := 1.0
+	if len(ac) > 0 {
+		actualMaximum := 0.0
+		for _, factor := range ac {
+			actualMaximum = math.Max(actualMaximum, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		hash.WriteString(encodeBase83(quantisedMaximum, 1))
+	} else {
+		hash.WriteString(encodeBase83(0, 1))
+	}
+
+	hash.WriteString(encodeBase83(linearToSrgb(dc[0])<<16+linearToSrgb(dc[1])<<8+linearToSrgb(dc[2]), 4))
+
+	for _, factor := range ac {
+		quantise := func(value float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(value/maximumValue, 0.5)*9+9.5))))
+		}
+		hash.WriteString(encodeBase83(quantise(factor[0])*19*19+quantise(factor[1])*19+quantise(factor[2]), 2))
+	}
+
+	return hash.String()
+}
+
+// dominantColorOf mengelompokkan warna ke 4 bit per channel, lalu mengambil rata-rata kelompok terbanyak.
+// Pixel yang hampir transparan tidak dihitung
+func dominantColorOf(img *image.NRGBA) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+
+	buckets := make(map[int]*bucket)
+	var dominant *bucket
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			pixel := img.NRGBAAt(x, y)
+			if pixel.A < 128 {
+				continue
+			}
+
+			key := int(pixel.R>>4)<<8 | int(pixel.G>>4)<<4 | int(pixel.B>>4)
+			item, ok := buckets[key]
+			if !ok {
+				item = new(bucket)
+				buckets[key] = item
+			}
+
+			item.count++
+			item.r += int(pixel.R)
+			item.g += int(pixel.G)
+			item.b += int(pixel.B)
+
+			if dominant == nil || item.count > dominant.count {
+				dominant = item
+			}
+		}
+	}
+
+	if dominant == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", dominant.r/dominant.count, dominant.g/dominant.count, dominant.b/dominant.count)
+}
+
+func encodeBase83(value, length int) string {
+	encoded := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		encoded[i-1] = blurHashCharacters[digit]
+	}
+
+	return string(encoded)
+}
+
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSrgb(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
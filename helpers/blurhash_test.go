@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func nrgbaImage(width, height int, pixel func(x, y int) color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, pixel(x, y))
+		}
+	}
+
+	return img
+}
+
+// hash yang diharapkan dihasilkan oleh encoder referensi woltapp/blurhash (encode.ts)
+// dari pixel dan jumlah komponen yang sama
+func TestEncodeBlurHash(t *testing.T) {
+	tests := []struct {
+		name        string
+		img         *image.NRGBA
+		xComponents int
+		yComponents int
+		want        string
+	}{
+		{
+			name:        "hitam",
+			img:         nrgbaImage(32, 24, func(x, y int) color.NRGBA { return color.NRGBA{A: 255} }),
+			xComponents: 4,
+			yComponents: 3,
+			want:        "L00000fQfQfQfQfQfQfQfQfQfQfQ",
+		},
+		{
+			name:        "putih",
+			img:         nrgbaImage(32, 24, func(x, y int) color.NRGBA { return color.NRGBA{R: 255, G: 255, B: 255, A: 255} }),
+			xComponents: 4,
+			yComponents: 3,
+			want:        "LDTSUA_3fQ_3~qoffQoffQfQfQfQ",
+		},
+		{
+			name:        "hanya komponen dc",
+			img:         nrgbaImage(32, 24, func(x, y int) color.NRGBA { return color.NRGBA{R: 200, G: 120, B: 40, A: 255} }),
+			xComponents: 1,
+			yComponents: 1,
+			want:        "00M}7u",
+		},
+		{
+			name: "gradient landscape",
+			img: nrgbaImage(32, 24, func(x, y int) color.NRGBA {
+				return color.NRGBA{R: uint8(x * 8), G: uint8(y * 10), B: 128, A: 255}
+			}),
+			xComponents: 4,
+			yComponents: 3,
+			want:        "LxH27k2swxX8mHWWjtf7gJfjfQfj",
+		},
+		{
+			name: "kotak portrait",
+			img: nrgbaImage(24, 32, func(x, y int) color.NRGBA {
+				if (x < 12) == (y < 16) {
+					return color.NRGBA{R: 255, A: 255}
+				}
+				return color.NRGBA{B: 255, A: 255}
+			}),
+			xComponents: 3,
+			yComponents: 4,
+			want:        "T+LjfLsXfQsX|TsRfQsRjssXJqWr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeBlurHash(tt.img, tt.xComponents, tt.yComponents); got != tt.want {
+				t.Fatalf("encodeBlurHash() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		name          string
+		img           image.Image
+		wantLength    int
+		wantDominant  string
+		wantComponent byte
+	}{
+		{
+			name:          "landscape memakai 4x3 komponen",
+			img:           nrgbaImage(64, 48, func(x, y int) color.NRGBA { return color.NRGBA{R: 16, G: 32, B: 48, A: 255} }),
+			wantLength:    28,
+			wantDominant:  "#102030",
+			wantComponent: 'L',
+		},
+		{
+			name: "portrait memakai 3x4 komponen",
+			img: nrgbaImage(30, 90, func(x, y int) color.NRGBA {
+				if y < 60 {
+					return color.NRGBA{R: 250, G: 250, B: 250, A: 255}
+				}
+				return color.NRGBA{A: 255}
+			}),
+			wantLength:    28,
+			wantDominant:  "#fafafa",
+			wantComponent: 'T',
+		},
+		{
+			name:         "transparan tidak punya warna dominan",
+			img:          nrgbaImage(10, 10, func(x, y int) color.NRGBA { return color.NRGBA{R: 255} }),
+			wantLength:   28,
+			wantDominant: "",
+		},
+		{
+			name: "gambar kosong",
+			img:  image.NewNRGBA(image.Rect(0, 0, 0, 0)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blurHash, dominantColor := Placeholder(tt.img)
+			if len(blurHash) != tt.wantLength || dominantColor != tt.wantDominant {
+				t.Fatalf("Placeholder() = %q, %q, want length %d and %q", blurHash, dominantColor, tt.wantLength, tt.wantDominant)
+			}
+			if tt.wantComponent != 0 && blurHash[0] != tt.wantComponent {
+				t.Fatalf("Placeholder() size flag = %c, want %c", blurHash[0], tt.wantComponent)
+			}
+		})
+	}
+}
@@ -31,18 +31,55 @@ var (
 	ErrPhotoNotFound         = errors.New("photo not found")
 	ErrCommentNotFound       = errors.New("comment not found")
 	ErrTagNotFound           = errors.New("tag not found")
+	ErrSessionNotFound       = errors.New("session not found")
+	ErrFileNotFound          = errors.New("file not found")
 	ErrFileNotSupported      = errors.New("file not supported")
-	errFileSizeNotValid      = errors.New("maximal file size is 2 MB")
+	errFileSizeNotValid      = errors.New("file size exceeds the limit for this media type")
+	ErrCursorInvalid         = errors.New("cursor invalid")
+	ErrPageLimitInvalid      = errors.New("limit must be a positive number")
+	ErrRoleInvalid           = errors.New("role must be one of user, moderator or admin")
+	ErrUserSuspended         = errors.New("your account is suspended")
+	ErrResetCodeInvalid      = errors.New("reset code is invalid or expired")
+	ErrResetCodeRequired     = errors.New("code is required")
+	ErrImageTooLarge         = errors.New("image dimensions are too large")
+	ErrMediaRequired         = errors.New("a post must contain at least one photo")
+	ErrTooManyMedia          = errors.New("a post can contain at most 10 photos")
+	ErrMediaOrderInvalid     = errors.New("media must only list slides of this post, each once")
+	ErrMediaTooLong          = errors.New("media duration exceeds the limit for this media type")
+	ErrUploadLengthInvalid   = errors.New("upload length is invalid")
+	ErrUploadChunkTooLarge   = errors.New("chunk exceeds the declared upload length")
+	ErrUploadIncomplete      = errors.New("upload is not complete yet")
+	ErrUploadSessionNotFound = errors.New("upload session not found")
+	ErrUploadOffsetMismatch  = errors.New("upload offset does not match the current offset")
+	ErrUploadSessionBusy     = errors.New("upload session is being written by another request")
+	ErrUploadOffsetInvalid   = errors.New("upload offset is invalid")
+	ErrUploadQuotaExceeded   = errors.New("too many unfinished uploads")
+	ErrPasswordResetLimited  = errors.New("too many password reset requests, try again later")
+	ErrMediaNotFound         = errors.New("media not found")
+	ErrMediaAlreadyClaimed   = errors.New("media is already attached to a post")
+	ErrDuplicateMedia        = errors.New("image is too similar to one of your recent photos")
+	ErrMediaAlreadyExists    = errors.New("media for this file already exists")
+	ErrUploadConfirmBusy     = errors.New("upload is being confirmed by another request")
+	ErrCommentSortInvalid    = errors.New("sort must be one of newest or top")
+	ErrTagQueryRequired      = errors.New("q is required")
+	ErrTagInvalid            = errors.New("tags may only contain letters, digits and underscores, up to 100 characters")
+	ErrTooManyTags           = errors.New("a post can contain at most 30 tags")
 
 	ErrCommentMessageRequired = errors.New("message is required")
 
-	ErrHeaderNotProvide  = errors.New("headers not provide")
-	ErrInvalidHeaderType = errors.New("invalid header type")
-	ErrTokenNotVerified  = errors.New("token not verified")
+	ErrHeaderNotProvide   = errors.New("headers not provide")
+	ErrInvalidHeaderType  = errors.New("invalid header type")
+	ErrTokenNotVerified   = errors.New("token not verified")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, please sign in again")
 
 	// general
 	ErrFailedSendEmail = errors.New("failed send email")
 	ErrRepository      = errors.New("error repository")
+	ErrVideoProcessing = errors.New("failed to process video")
+
+	ErrDirectUploadNotSupported = errors.New("direct upload is not supported by the storage driver")
+	ErrAuthUnavailable          = errors.New("authentication is temporarily unavailable")
 )
 
 type Error struct {
@@ -83,29 +120,73 @@ var (
 	ErrorLinkExpired            = NewError(ErrLinkExpired.Error(), "40008", http.StatusBadRequest)
 	ErrorFileNotSupported       = NewError(ErrFileNotSupported.Error(), "40009", http.StatusBadRequest)
 	ErrorFileSizeNotValid       = NewError(errFileSizeNotValid.Error(), "40410", http.StatusBadRequest)
+	ErrorCursorInvalid          = NewError(ErrCursorInvalid.Error(), "40010", http.StatusBadRequest)
+	ErrorPageLimitInvalid       = NewError(ErrPageLimitInvalid.Error(), "40011", http.StatusBadRequest)
+	ErrorRoleInvalid            = NewError(ErrRoleInvalid.Error(), "40012", http.StatusBadRequest)
+	ErrorResetCodeInvalid       = NewError(ErrResetCodeInvalid.Error(), "40013", http.StatusBadRequest)
+	ErrorResetCodeRequired      = NewError(ErrResetCodeRequired.Error(), "40014", http.StatusBadRequest)
+	ErrorImageTooLarge          = NewError(ErrImageTooLarge.Error(), "40015", http.StatusBadRequest)
+	ErrorMediaRequired          = NewError(ErrMediaRequired.Error(), "40016", http.StatusBadRequest)
+	ErrorTooManyMedia           = NewError(ErrTooManyMedia.Error(), "40017", http.StatusBadRequest)
+	ErrorMediaOrderInvalid      = NewError(ErrMediaOrderInvalid.Error(), "40018", http.StatusBadRequest)
+	ErrorMediaTooLong           = NewError(ErrMediaTooLong.Error(), "40019", http.StatusBadRequest)
+	ErrorUploadLengthInvalid    = NewError(ErrUploadLengthInvalid.Error(), "40020", http.StatusBadRequest)
+	ErrorUploadChunkTooLarge    = NewError(ErrUploadChunkTooLarge.Error(), "40021", http.StatusBadRequest)
+	ErrorUploadIncomplete       = NewError(ErrUploadIncomplete.Error(), "40022", http.StatusBadRequest)
+	ErrorCommentSortInvalid     = NewError(ErrCommentSortInvalid.Error(), "40023", http.StatusBadRequest)
+	ErrorTagQueryRequired       = NewError(ErrTagQueryRequired.Error(), "40024", http.StatusBadRequest)
+	ErrorUploadOffsetInvalid    = NewError(ErrUploadOffsetInvalid.Error(), "40025", http.StatusBadRequest)
+	ErrorTagInvalid             = NewError(ErrTagInvalid.Error(), "40026", http.StatusBadRequest)
+	ErrorTooManyTags            = NewError(ErrTooManyTags.Error(), "40027", http.StatusBadRequest)
 
 	// conflict
-	ErrorEmailAlreadyUsed    = NewError(ErrEmailAlreadyUserd.Error(), "40901", http.StatusConflict)
-	ErrorUsernameAlreadyUsed = NewError(ErrUsernameAlreadyUsed.Error(), "40902", http.StatusConflict)
+	ErrorEmailAlreadyUsed     = NewError(ErrEmailAlreadyUserd.Error(), "40901", http.StatusConflict)
+	ErrorUsernameAlreadyUsed  = NewError(ErrUsernameAlreadyUsed.Error(), "40902", http.StatusConflict)
+	ErrorUploadOffsetMismatch = NewError(ErrUploadOffsetMismatch.Error(), "40903", http.StatusConflict)
+	ErrorUploadSessionBusy    = NewError(ErrUploadSessionBusy.Error(), "40904", http.StatusConflict)
+	ErrorMediaAlreadyClaimed  = NewError(ErrMediaAlreadyClaimed.Error(), "40905", http.StatusConflict)
+	ErrorDuplicateMedia       = NewError(ErrDuplicateMedia.Error(), "40906", http.StatusConflict)
+	ErrorMediaAlreadyExists   = NewError(ErrMediaAlreadyExists.Error(), "40907", http.StatusConflict)
+	ErrorUploadConfirmBusy    = NewError(ErrUploadConfirmBusy.Error(), "40908", http.StatusConflict)
 
 	// not found
-	ErrorEmailNotFound        = NewError(ErrEmailNotFound.Error(), "40401", http.StatusNotFound)
-	ErrorRefreshTokenNotFound = NewError(ErrRefreshTokenNotFound.Error(), "40402", http.StatusNotFound)
-	ErrorUserNotFound         = NewError(ErrUserNotFound.Error(), "40403", http.StatusNotFound)
-	ErrorPhotoNotFound        = NewError(ErrPhotoNotFound.Error(), "40404", http.StatusNotFound)
-	ErrorCommentNotFound      = NewError(ErrCommentNotFound.Error(), "40405", http.StatusNotFound)
-	ErrorTagNotFound          = NewError(ErrTagNotFound.Error(), "40406", http.StatusNotFound)
+	ErrorEmailNotFound         = NewError(ErrEmailNotFound.Error(), "40401", http.StatusNotFound)
+	ErrorRefreshTokenNotFound  = NewError(ErrRefreshTokenNotFound.Error(), "40402", http.StatusNotFound)
+	ErrorUserNotFound          = NewError(ErrUserNotFound.Error(), "40403", http.StatusNotFound)
+	ErrorPhotoNotFound         = NewError(ErrPhotoNotFound.Error(), "40404", http.StatusNotFound)
+	ErrorCommentNotFound       = NewError(ErrCommentNotFound.Error(), "40405", http.StatusNotFound)
+	ErrorTagNotFound           = NewError(ErrTagNotFound.Error(), "40406", http.StatusNotFound)
+	ErrorSessionNotFound       = NewError(ErrSessionNotFound.Error(), "40407", http.StatusNotFound)
+	ErrorFileNotFound          = NewError(ErrFileNotFound.Error(), "40408", http.StatusNotFound)
+	ErrorUploadSessionNotFound = NewError(ErrUploadSessionNotFound.Error(), "40409", http.StatusNotFound)
+	ErrorMediaNotFound         = NewError(ErrMediaNotFound.Error(), "40411", http.StatusNotFound)
 
 	// unauthorized
-	ErrorPasswordNotMatch  = NewError(ErrPasswordNotMatch.Error(), "40101", http.StatusUnauthorized)
-	ErrorEmailNotVerified  = NewError(ErrEmailNotVerified.Error(), "40102", http.StatusUnauthorized)
-	ErrorHeaderNotProvide  = NewError(ErrHeaderNotProvide.Error(), "40103", http.StatusUnauthorized)
-	ErrorInvalidHeaderType = NewError(ErrInvalidHeaderType.Error(), "40104", http.StatusUnauthorized)
-	ErrorTokenNotVerified  = NewError(ErrTokenNotVerified.Error(), "40105", http.StatusUnauthorized)
+	ErrorPasswordNotMatch   = NewError(ErrPasswordNotMatch.Error(), "40101", http.StatusUnauthorized)
+	ErrorEmailNotVerified   = NewError(ErrEmailNotVerified.Error(), "40102", http.StatusUnauthorized)
+	ErrorHeaderNotProvide   = NewError(ErrHeaderNotProvide.Error(), "40103", http.StatusUnauthorized)
+	ErrorInvalidHeaderType  = NewError(ErrInvalidHeaderType.Error(), "40104", http.StatusUnauthorized)
+	ErrorTokenNotVerified   = NewError(ErrTokenNotVerified.Error(), "40105", http.StatusUnauthorized)
+	ErrorRefreshTokenReused = NewError(ErrRefreshTokenReused.Error(), "40106", http.StatusUnauthorized)
+	ErrorTokenRevoked       = NewError(ErrTokenRevoked.Error(), "40107", http.StatusUnauthorized)
+
+	// forbidden
+	ErrorUserSuspended = NewError(ErrUserSuspended.Error(), "40301", http.StatusForbidden)
+
+	// too many requests
+	ErrorUploadQuotaExceeded  = NewError(ErrUploadQuotaExceeded.Error(), "42901", http.StatusTooManyRequests)
+	ErrorPasswordResetLimited = NewError(ErrPasswordResetLimited.Error(), "42902", http.StatusTooManyRequests)
 
 	// internal server error
 	ErrorRepository      = NewError(ErrRepository.Error(), "50001", http.StatusInternalServerError)
 	ErrorFailedSendEmail = NewError(ErrFailedSendEmail.Error(), "50002", http.StatusInternalServerError)
+	ErrorVideoProcessing = NewError(ErrVideoProcessing.Error(), "50003", http.StatusInternalServerError)
+
+	// not implemented
+	ErrorDirectUploadNotSupported = NewError(ErrDirectUploadNotSupported.Error(), "50101", http.StatusNotImplemented)
+
+	// service unavailable
+	ErrorAuthUnavailable = NewError(ErrAuthUnavailable.Error(), "50301", http.StatusServiceUnavailable)
 )
 
 var (
@@ -130,10 +211,48 @@ var (
 		ErrPhotoNotFound.Error():          ErrorPhotoNotFound,
 		ErrCommentNotFound.Error():        ErrorCommentNotFound,
 		ErrTagNotFound.Error():            ErrorTagNotFound,
+		ErrSessionNotFound.Error():        ErrorSessionNotFound,
+		ErrFileNotFound.Error():           ErrorFileNotFound,
 		ErrFileNotSupported.Error():       ErrorFileNotSupported,
 		ErrHeaderNotProvide.Error():       ErrorHeaderNotProvide,
 		ErrInvalidHeaderType.Error():      ErrorInvalidHeaderType,
 		ErrTokenNotVerified.Error():       ErrorTokenNotVerified,
+		ErrRefreshTokenReused.Error():     ErrorRefreshTokenReused,
+		ErrTokenRevoked.Error():           ErrorTokenRevoked,
 		errFileSizeNotValid.Error():       ErrorFileSizeNotValid,
+		ErrForbiddenAccess.Error():        ErrorForbiddenAccess,
+		ErrRoleInvalid.Error():            ErrorRoleInvalid,
+		ErrUserSuspended.Error():          ErrorUserSuspended,
+		ErrCursorInvalid.Error():          ErrorCursorInvalid,
+		ErrPageLimitInvalid.Error():       ErrorPageLimitInvalid,
+		ErrResetCodeInvalid.Error():       ErrorResetCodeInvalid,
+		ErrResetCodeRequired.Error():      ErrorResetCodeRequired,
+		ErrImageTooLarge.Error():          ErrorImageTooLarge,
+		ErrMediaRequired.Error():          ErrorMediaRequired,
+		ErrTooManyMedia.Error():           ErrorTooManyMedia,
+		ErrMediaOrderInvalid.Error():      ErrorMediaOrderInvalid,
+		ErrMediaTooLong.Error():           ErrorMediaTooLong,
+		ErrVideoProcessing.Error():        ErrorVideoProcessing,
+		ErrUploadLengthInvalid.Error():    ErrorUploadLengthInvalid,
+		ErrUploadChunkTooLarge.Error():    ErrorUploadChunkTooLarge,
+		ErrUploadIncomplete.Error():       ErrorUploadIncomplete,
+		ErrUploadSessionNotFound.Error():  ErrorUploadSessionNotFound,
+		ErrUploadOffsetMismatch.Error():   ErrorUploadOffsetMismatch,
+		ErrUploadSessionBusy.Error():      ErrorUploadSessionBusy,
+		ErrMediaNotFound.Error():          ErrorMediaNotFound,
+		ErrMediaAlreadyClaimed.Error():    ErrorMediaAlreadyClaimed,
+		ErrDuplicateMedia.Error():         ErrorDuplicateMedia,
+		ErrMediaAlreadyExists.Error():     ErrorMediaAlreadyExists,
+		ErrUploadConfirmBusy.Error():      ErrorUploadConfirmBusy,
+		ErrCommentSortInvalid.Error():     ErrorCommentSortInvalid,
+		ErrTagQueryRequired.Error():       ErrorTagQueryRequired,
+		ErrUploadOffsetInvalid.Error():    ErrorUploadOffsetInvalid,
+		ErrTagInvalid.Error():             ErrorTagInvalid,
+		ErrTooManyTags.Error():            ErrorTooManyTags,
+		ErrUploadQuotaExceeded.Error():    ErrorUploadQuotaExceeded,
+		ErrPasswordResetLimited.Error():   ErrorPasswordResetLimited,
+
+		ErrDirectUploadNotSupported.Error(): ErrorDirectUploadNotSupported,
+		ErrAuthUnavailable.Error():          ErrorAuthUnavailable,
 	}
 )
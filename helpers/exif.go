@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	exifOrientationTag = 0x0112
+	jpegMarkerAPP1     = 0xE1
+	jpegMarkerSOS      = 0xDA
+)
+
+// ExifOrientation membaca tag orientation (1-8) dari segmen APP1 sebuah JPEG.
+// Kalau tidak ada data exif atau datanya rusak, dianggap 1 (tanpa rotasi)
+func ExifOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(content) {
+		if content[offset] != 0xFF {
+			return 1
+		}
+
+		marker := content[offset+1]
+		// marker tanpa panjang segmen, misalnya padding 0xFF
+		if marker == 0xFF {
+			offset++
+			continue
+		}
+
+		// setelah start of scan isinya data gambar, exif tidak mungkin ada lagi
+		if marker == jpegMarkerSOS {
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(content[offset+2 : offset+4]))
+		if length < 2 || offset+2+length > len(content) {
+			return 1
+		}
+
+		segment := content[offset+4 : offset+2+length]
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		offset += 2 + length
+	}
+
+	return 1
+}
+
+// tiffOrientation mencari tag orientation di IFD0 header TIFF milik exif
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:8]))
+	if ifdOffset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientationTag {
+			continue
+		}
+
+		// tipe SHORT, nilainya ada di 2 byte pertama field value
+		orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+
+		return orientation
+	}
+
+	return 1
+}
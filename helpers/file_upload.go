@@ -2,51 +2,61 @@ package helpers
 
 import (
 	"io"
-	"mime/multipart"
+	"mime"
 	"net/http"
+	"time"
 )
 
 const (
 	MAX_FILE_SIZE = 2
 )
 
-func IsImageFile(file multipart.File) (bool, error) {
+// MediaLimit adalah batas ukuran (MB) dan durasi untuk satu tipe media,
+// MaxDuration nol berarti tipe tersebut tidak punya durasi
+type MediaLimit struct {
+	MaxSize     int64
+	MaxDuration time.Duration
+}
+
+// MediaLimits berisi tipe file yang boleh diupload beserta batasnya
+var MediaLimits = map[string]MediaLimit{
+	"image/jpeg": {MaxSize: MAX_FILE_SIZE},
+	"image/png":  {MaxSize: MAX_FILE_SIZE},
+	"image/gif":  {MaxSize: 8, MaxDuration: 15 * time.Second},
+	"image/webp": {MaxSize: 8, MaxDuration: 15 * time.Second},
+	"video/mp4":  {MaxSize: 50, MaxDuration: 60 * time.Second},
+}
+
+// DetectMediaType membaca header file untuk menentukan content type dan memastikan tipenya didukung
+func DetectMediaType(file io.ReadSeeker) (string, error) {
 	// Baca maksimal 512 byte untuk mendeteksi tipe file
 	buffer := make([]byte, 512)
-	_, err := file.Read(buffer)
+	n, err := file.Read(buffer)
 	if err != nil {
-		return false, err
+		return "", err
 	}
+	buffer = buffer[:n]
 
 	// Set posisi kembali ke awal file setelah membaca buffer
 	_, err = file.Seek(0, io.SeekStart)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	// Deteksi tipe file
 	fileType := http.DetectContentType(buffer)
 
-	// Periksa tipe file yang didukung
-	supportedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-	}
-
-	if supportedTypes[fileType] {
-		return true, nil
+	if _, ok := MediaLimits[fileType]; !ok {
+		// Tipe file tidak didukung
+		return "", ErrFileNotSupported
 	}
 
-	// Tipe file tidak didukung
-	return false, ErrFileNotSupported
+	return fileType, nil
 }
 
-func IsFileSizeValid(file *multipart.FileHeader) error {
+func IsFileSizeValid(fileSize int64, contentType string) error {
 	// Batas maksimum ukuran file dalam byte
-	maxSize := int64(MAX_FILE_SIZE) * 1024 * 1024
-
-	// Baca ukuran file
-	fileSize := file.Size
+	maxSize := MediaLimits[contentType].MaxSize * 1024 * 1024
 
 	// Periksa apakah ukuran file melebihi batas
 	if fileSize > maxSize {
@@ -55,3 +65,46 @@ func IsFileSizeValid(file *multipart.FileHeader) error {
 
 	return nil
 }
+
+// MaxUploadSize adalah batas ukuran terbesar dari semua tipe media, dipakai sebelum tipe file diketahui
+func MaxUploadSize() int64 {
+	var maxSize int64
+	for _, limit := range MediaLimits {
+		maxSize = max(maxSize, limit.MaxSize)
+	}
+
+	return maxSize * 1024 * 1024
+}
+
+// IsDurationValid memeriksa durasi gif, webp animasi atau video terhadap batas tipe filenya
+func IsDurationValid(duration time.Duration, contentType string) error {
+	maxDuration := MediaLimits[contentType].MaxDuration
+	if maxDuration > 0 && duration > maxDuration {
+		return ErrMediaTooLong
+	}
+
+	return nil
+}
+
+// FileExtension menentukan ekstensi file dari content type hasil http.DetectContentType
+func FileExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "video/mp4":
+		return ".mp4"
+	}
+
+	extensions, err := mime.ExtensionsByType(contentType)
+	if err != nil || len(extensions) == 0 {
+		return ""
+	}
+
+	return extensions[0]
+}
@@ -0,0 +1,135 @@
+package helpers
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// MAX_GIF_DECODED_PIXELS membatasi jumlah piksel seluruh frame gif. File gif kecil bisa berisi
+// ribuan frame seukuran canvas yang memakan memori sangat besar saat di-decode, di server maupun di client
+const MAX_GIF_DECODED_PIXELS = 100_000_000
+
+const (
+	gifBlockExtension       = 0x21
+	gifBlockImageDescriptor = 0x2C
+	gifBlockTrailer         = 0x3B
+	gifExtensionGraphic     = 0xF9
+	gifFlagColorTable       = 0x80
+)
+
+// gifInfo adalah hasil membaca struktur block gif tanpa men-decode data LZW-nya
+type gifInfo struct {
+	Width         int
+	Height        int
+	Frames        int
+	Duration      time.Duration
+	DecodedPixels int64
+}
+
+// parseGif menelusuri block gif untuk menghitung frame, durasi dan total piksel
+// sebelum ada frame yang di-decode
+func parseGif(content []byte) (*gifInfo, error) {
+	if len(content) < 13 || (string(content[:6]) != "GIF87a" && string(content[:6]) != "GIF89a") {
+		return nil, ErrFileNotSupported
+	}
+
+	info := &gifInfo{
+		Width:  int(binary.LittleEndian.Uint16(content[6:8])),
+		Height: int(binary.LittleEndian.Uint16(content[8:10])),
+	}
+
+	offset := 13
+	if content[10]&gifFlagColorTable != 0 {
+		offset += gifColorTableSize(content[10])
+	}
+
+	// delay dari graphic control extension berlaku untuk frame setelahnya
+	var pendingDelay time.Duration
+	for {
+		if offset >= len(content) {
+			return nil, ErrFileNotSupported
+		}
+
+		block := content[offset]
+		offset++
+
+		switch block {
+		case gifBlockTrailer:
+			return info, nil
+		case gifBlockExtension:
+			if offset >= len(content) {
+				return nil, ErrFileNotSupported
+			}
+			label := content[offset]
+			offset++
+
+			if label == gifExtensionGraphic && offset+5 <= len(content) && content[offset] >= 4 {
+				// delay gif dalam satuan 1/100 detik
+				pendingDelay = time.Duration(binary.LittleEndian.Uint16(content[offset+2:offset+4])) * 10 * time.Millisecond
+			}
+
+			end, ok := skipGifSubBlocks(content, offset)
+			if !ok {
+				return nil, ErrFileNotSupported
+			}
+			offset = end
+		case gifBlockImageDescriptor:
+			if offset+9 > len(content) {
+				return nil, ErrFileNotSupported
+			}
+			left := int(binary.LittleEndian.Uint16(content[offset : offset+2]))
+			top := int(binary.LittleEndian.Uint16(content[offset+2 : offset+4]))
+			width := int(binary.LittleEndian.Uint16(content[offset+4 : offset+6]))
+			height := int(binary.LittleEndian.Uint16(content[offset+6 : offset+8]))
+			flags := content[offset+8]
+			offset += 9
+
+			if left+width > info.Width || top+height > info.Height {
+				return nil, ErrFileNotSupported
+			}
+
+			info.Frames++
+			info.Duration += pendingDelay
+			pendingDelay = 0
+
+			info.DecodedPixels += int64(width) * int64(height)
+			if info.DecodedPixels > MAX_GIF_DECODED_PIXELS {
+				return nil, ErrImageTooLarge
+			}
+
+			if flags&gifFlagColorTable != 0 {
+				offset += gifColorTableSize(flags)
+			}
+
+			// satu byte ukuran kode minimum LZW sebelum sub-block data
+			end, ok := skipGifSubBlocks(content, offset+1)
+			if !ok {
+				return nil, ErrFileNotSupported
+			}
+			offset = end
+		default:
+			return nil, ErrFileNotSupported
+		}
+	}
+}
+
+// gifColorTableSize menghitung panjang color table dari 3 bit terbawah flags
+func gifColorTableSize(flags byte) int {
+	return 3 * (1 << ((flags & 0x07) + 1))
+}
+
+// skipGifSubBlocks melewati rangkaian sub-block sampai block terminator
+// dan mengembalikan offset setelahnya
+func skipGifSubBlocks(content []byte, offset int) (int, bool) {
+	for offset < len(content) {
+		size := int(content[offset])
+		offset++
+
+		if size == 0 {
+			return offset, true
+		}
+		offset += size
+	}
+
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+func encodeGif(t *testing.T, width, height int, frames []image.Rectangle, delays []int) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.Black, color.White}
+	animation := &gif.GIF{
+		Config: image.Config{Width: width, Height: height, ColorModel: palette},
+	}
+	for i, bounds := range frames {
+		animation.Image = append(animation.Image, image.NewPaletted(bounds, palette))
+		animation.Delay = append(animation.Delay, delays[i])
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := gif.EncodeAll(buf, animation); err != nil {
+		t.Fatalf("EncodeAll() error %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// manyFramesGif membuat gif dengan frame 1x1 tetapi image descriptor-nya mengaku seukuran canvas,
+// meniru file kecil yang membengkak saat di-decode
+func manyFramesGif(width, height, frames int) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("GIF89a")
+	binary.Write(buf, binary.LittleEndian, uint16(width))
+	binary.Write(buf, binary.LittleEndian, uint16(height))
+	buf.Write([]byte{0x80, 0, 0})
+	buf.Write([]byte{0, 0, 0, 255, 255, 255})
+
+	for i := 0; i < frames; i++ {
+		buf.WriteByte(gifBlockImageDescriptor)
+		binary.Write(buf, binary.LittleEndian, uint16(0))
+		binary.Write(buf, binary.LittleEndian, uint16(0))
+		binary.Write(buf, binary.LittleEndian, uint16(width))
+		binary.Write(buf, binary.LittleEndian, uint16(height))
+		buf.WriteByte(0)
+		// kode minimum LZW lalu satu sub-block kosong
+		buf.Write([]byte{2, 1, 0x44, 0})
+	}
+	buf.WriteByte(gifBlockTrailer)
+
+	return buf.Bytes()
+}
+
+func TestParseGif(t *testing.T) {
+	full := image.Rect(0, 0, 40, 30)
+
+	tests := []struct {
+		name       string
+		content    []byte
+		wantFrames int
+		wantDelay  time.Duration
+		wantPixels int64
+		wantErr    error
+	}{
+		{
+			name:       "gif statis",
+			content:    encodeGif(t, 40, 30, []image.Rectangle{full}, []int{0}),
+			wantFrames: 1,
+			wantPixels: 40 * 30,
+		},
+		{
+			name:       "animasi menjumlahkan delay",
+			content:    encodeGif(t, 40, 30, []image.Rectangle{full, image.Rect(5, 5, 15, 15), full}, []int{10, 25, 5}),
+			wantFrames: 3,
+			wantDelay:  400 * time.Millisecond,
+			wantPixels: 40*30*2 + 10*10,
+		},
+		{
+			name:    "piksel melewati batas",
+			content: manyFramesGif(2048, 2048, MAX_GIF_DECODED_PIXELS/(2048*2048)+1),
+			wantErr: ErrImageTooLarge,
+		},
+		{
+			name:    "bukan gif",
+			content: []byte("\x89PNG\r\n\x1a\n0000000"),
+			wantErr: ErrFileNotSupported,
+		},
+		{
+			name:    "terpotong tanpa trailer",
+			content: manyFramesGif(10, 10, 2)[:40],
+			wantErr: ErrFileNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseGif(tt.content)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("parseGif() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseGif() unexpected error %v", err)
+			}
+
+			if info.Frames != tt.wantFrames || info.Duration != tt.wantDelay || info.DecodedPixels != tt.wantPixels {
+				t.Fatalf("parseGif() = %+v, want frames %d delay %v pixels %d", info, tt.wantFrames, tt.wantDelay, tt.wantPixels)
+			}
+		})
+	}
+}
+
+func TestProcessGif(t *testing.T) {
+	content := encodeGif(t, 40, 30, []image.Rectangle{image.Rect(0, 0, 40, 30), image.Rect(0, 0, 20, 20)}, []int{50, 50})
+
+	processed, err := processGif(content)
+	if err != nil {
+		t.Fatalf("processGif() unexpected error %v", err)
+	}
+
+	if processed.MediaType != domain.MediaTypeAnimation || processed.Duration != time.Second {
+		t.Fatalf("processGif() media type %s duration %v, want %s 1s", processed.MediaType, processed.Duration, domain.MediaTypeAnimation)
+	}
+	if processed.Full.Width != 40 || processed.Full.Height != 30 || len(processed.Thumbnail.Content) == 0 {
+		t.Fatalf("processGif() renditions = %+v", processed)
+	}
+}
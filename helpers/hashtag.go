@@ -0,0 +1,77 @@
+package helpers
+
+import "github.com/ariwiraa/my-gram/domain"
+
+// MAX_HASHTAGS membatasi jumlah tag berbeda dalam satu foto
+const MAX_HASHTAGS = 30
+
+// ParseHashtags mengambil #hashtag dari text yang sudah dinormalisasi, tanpa duplikat dan sesuai urutan kemunculan.
+// # hanya dihitung di awal text atau setelah karakter yang bukan bagian tag, jadi url dengan fragment tidak ikut terbaca
+func ParseHashtags(text string) []string {
+	var tags []string
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && (domain.IsTagRune(runes[i-1]) || runes[i-1] == '#' || runes[i-1] == '/')) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && domain.IsTagRune(runes[end]) {
+			end++
+		}
+
+		tags = AppendTag(tags, string(runes[i+1:end]))
+		i = end - 1
+	}
+
+	return tags
+}
+
+// NormalizeTags menormalisasi tag yang diisi user secara eksplisit. Berbeda dengan #hashtag di caption,
+// tag yang tidak valid atau melebihi MAX_HASHTAGS ditolak supaya user tahu tag-nya tidak tersimpan
+func NormalizeTags(names []string) ([]string, error) {
+	var tags []string
+	for _, name := range names {
+		tag := domain.NormalizeTag(name)
+		if tag == "" {
+			return nil, ErrTagInvalid
+		}
+
+		if containsTag(tags, tag) {
+			continue
+		}
+
+		if len(tags) == MAX_HASHTAGS {
+			return nil, ErrTooManyTags
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// AppendTag menormalisasi name lalu menambahkannya ke tags kalau valid, belum ada dan jumlahnya belum mencapai MAX_HASHTAGS
+func AppendTag(tags []string, name string) []string {
+	tag := domain.NormalizeTag(name)
+	if tag == "" || len(tags) >= MAX_HASHTAGS {
+		return tags
+	}
+
+	if containsTag(tags, tag) {
+		return tags
+	}
+
+	return append(tags, tag)
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+
+	return false
+}
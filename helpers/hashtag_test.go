@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHashtags(t *testing.T) {
+	var manyHashtags, wantManyHashtags []string
+	for i := 0; i <= MAX_HASHTAGS; i++ {
+		manyHashtags = append(manyHashtags, fmt.Sprintf("#tag%02d", i))
+		if i < MAX_HASHTAGS {
+			wantManyHashtags = append(wantManyHashtags, fmt.Sprintf("tag%02d", i))
+		}
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "di awal dan tengah text", text: "#Senja di #pantai", want: []string{"senja", "pantai"}},
+		{name: "tanda baca mengakhiri tag", text: "liburan #bali, #lombok!", want: []string{"bali", "lombok"}},
+		{name: "duplikat setelah normalisasi", text: "#Café #CAFÉ #café", want: []string{"café"}},
+		{name: "fragment url tidak dihitung", text: "lihat https://example.com/#bagian dan foo#bar", want: nil},
+		{name: "tanda # ganda", text: "##dobel #", want: nil},
+		{name: "dibatasi", text: strings.Join(manyHashtags, " "), want: wantManyHashtags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseHashtags(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseHashtags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	var manyTags []string
+	for i := 0; i <= MAX_HASHTAGS; i++ {
+		manyTags = append(manyTags, fmt.Sprintf("tag%02d", i))
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "normalisasi dan buang duplikat", tags: []string{"#Golang", "golang", "Gram"}, want: []string{"golang", "gram"}},
+		{name: "tag tidak valid ditolak", tags: []string{"golang", "go-lang"}, wantErr: ErrTagInvalid},
+		{name: "tag kosong ditolak", tags: []string{" "}, wantErr: ErrTagInvalid},
+		{name: "terlalu banyak tag", tags: manyTags, wantErr: ErrTooManyTags},
+		{name: "duplikat tidak dihitung dalam batas", tags: append(manyTags[:MAX_HASHTAGS:MAX_HASHTAGS], "TAG00"), want: manyTags[:MAX_HASHTAGS]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizeTags() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("NormalizeTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
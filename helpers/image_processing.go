@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"golang.org/x/image/draw"
+)
+
+const (
+	// ukuran sisi terpanjang tiap rendition dalam pixel
+	IMAGE_FULL_DIMENSION      = 2048
+	IMAGE_MEDIUM_DIMENSION    = 1080
+	IMAGE_THUMBNAIL_DIMENSION = 320
+
+	// batas jumlah pixel sebelum decode, mencegah decompression bomb
+	IMAGE_MAX_PIXELS = 40_000_000
+
+	IMAGE_JPEG_QUALITY = 85
+)
+
+// MediaRendition adalah satu versi file hasil pipeline yang siap disimpan
+type MediaRendition struct {
+	Content     []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// ProcessedMedia berisi rendition full, medium dan thumbnail dari satu upload.
+// Untuk gif, webp dan video Full adalah file aslinya, sedangkan medium dan thumbnail
+// dibuat dari frame pertama. Poster hanya ada untuk video
+type ProcessedMedia struct {
+	MediaType string
+	Duration  time.Duration
+	Full      MediaRendition
+	Poster    *MediaRendition
+	Medium    MediaRendition
+	Thumbnail MediaRendition
+	// PerceptualHash, BlurHash dan DominantColor kosong kalau tidak ada frame yang bisa di-decode,
+	// misalnya webp animasi
+	PerceptualHash uint64
+	BlurHash       string
+	DominantColor  string
+}
+
+// ProcessImage men-decode JPEG/PNG, memutar gambar sesuai exif orientation,
+// membatasi dimensinya lalu meng-encode ulang tiap rendition.
+// Encoder bawaan Go tidak menulis metadata, jadi exif ikut terbuang
+func ProcessImage(content []byte) (*ProcessedMedia, error) {
+	imageConfig, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrFileNotSupported
+	}
+
+	if format != "jpeg" && format != "png" {
+		return nil, ErrFileNotSupported
+	}
+
+	if imageConfig.Width*imageConfig.Height > IMAGE_MAX_PIXELS {
+		return nil, ErrImageTooLarge
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrFileNotSupported
+	}
+
+	// resize dulu sebelum rotasi supaya rotasi dikerjakan di gambar yang lebih kecil,
+	// batasnya berlaku untuk sisi terpanjang jadi hasilnya sama
+	full := resizeToFit(source, IMAGE_FULL_DIMENSION)
+	if format == "jpeg" {
+		full = applyOrientation(full, ExifOrientation(content))
+	}
+
+	processed := &ProcessedMedia{MediaType: domain.MediaTypeImage}
+	analyzeImage(processed, full)
+
+	processed.Full, err = newRendition(full, format)
+	if err != nil {
+		return nil, err
+	}
+
+	processed.Medium, processed.Thumbnail, err = previewRenditions(full, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return processed, nil
+}
+
+// analyzeImage mengisi perceptual hash dan placeholder dari gambar yang sudah tegak
+func analyzeImage(processed *ProcessedMedia, img image.Image) {
+	processed.PerceptualHash = DHash(img)
+	processed.BlurHash, processed.DominantColor = Placeholder(img)
+}
+
+// previewRenditions membuat rendition medium dan thumbnail dari gambar yang sudah tegak
+func previewRenditions(img image.Image, format string) (medium MediaRendition, thumbnail MediaRendition, err error) {
+	mediumImage := resizeToFit(img, IMAGE_MEDIUM_DIMENSION)
+
+	medium, err = newRendition(mediumImage, format)
+	if err != nil {
+		return medium, thumbnail, err
+	}
+
+	thumbnail, err = newRendition(resizeToFit(mediumImage, IMAGE_THUMBNAIL_DIMENSION), format)
+	return medium, thumbnail, err
+}
+
+func newRendition(img image.Image, format string) (MediaRendition, error) {
+	encoded, contentType, err := encodeImage(img, format)
+	if err != nil {
+		return MediaRendition{}, err
+	}
+
+	bounds := img.Bounds()
+	return MediaRendition{
+		Content:     encoded,
+		ContentType: contentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
+
+// resizeToFit mengecilkan gambar sampai sisi terpanjangnya maxDimension, gambar kecil tidak diperbesar
+func resizeToFit(src image.Image, maxDimension int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxDimension && height <= maxDimension {
+		return src
+	}
+
+	if width >= height {
+		height = max(1, height*maxDimension/width)
+		width = maxDimension
+	} else {
+		width = max(1, width*maxDimension/height)
+		height = maxDimension
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// applyOrientation memutar atau membalik gambar supaya tampil tegak tanpa exif
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// orientation 5-8 menukar lebar dan tinggi
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // flip horizontal
+				sx, sy = width-1-x, y
+			case 3: // rotasi 180
+				sx, sy = width-1-x, height-1-y
+			case 4: // flip vertical
+				sx, sy = x, height-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotasi 90 searah jarum jam
+				sx, sy = y, height-1-x
+			case 7: // transverse
+				sx, sy = width-1-y, height-1-x
+			case 8: // rotasi 90 berlawanan arah jarum jam
+				sx, sy = width-1-y, x
+			}
+
+			dst.Set(x, y, src.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
+
+// encodeImage meng-encode ulang gambar dengan format aslinya, PNG tetap PNG supaya transparansi tidak hilang
+func encodeImage(img image.Image, format string) ([]byte, string, error) {
+	buffer := bytes.NewBuffer(nil)
+
+	if format == "png" {
+		err := png.Encode(buffer, img)
+		return buffer.Bytes(), "image/png", err
+	}
+
+	err := jpeg.Encode(buffer, img, &jpeg.Options{Quality: IMAGE_JPEG_QUALITY})
+	return buffer.Bytes(), "image/jpeg", err
+}
@@ -3,10 +3,12 @@ package helpers
 import (
 	"errors"
 	"github.com/ariwiraa/my-gram/config"
+	"github.com/ariwiraa/my-gram/domain"
 	"log"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
 var myAccessToken = []byte(config.LoadJwtConfig().GetTokenKey())
@@ -19,31 +21,67 @@ type jwtConfig struct {
 }
 
 type claims struct {
-	Id uint64
+	Id     uint64
+	Role   string `json:",omitempty"`
+	Family string `json:",omitempty"`
+	// IssuedAtMilli melengkapi iat yang hanya sampai detik, dipakai saat mencocokkan denylist per user
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
 	jwt.StandardClaims
 }
 
-func NewAccessToken(id uint64) *claims {
+// familyId ikut disimpan di access token supaya sesi yang sedang dipakai bisa dikenali dan dicabut
+func NewAccessToken(id uint64, role string, familyId string) *claims {
+	now := time.Now()
 	return &claims{
-		Id: id,
+		Id:            id,
+		Role:          role,
+		Family:        familyId,
+		IssuedAtMilli: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(accessTokenExpiry).Unix(),
+			Id:        uuid.NewString(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(accessTokenExpiry).Unix(),
 		},
 	}
 }
 
-func NewRefreshToken(id uint64) *claims {
+// AccessTokenExpiry adalah umur access token, dipakai sebagai ttl denylist per user
+func AccessTokenExpiry() time.Duration {
+	return accessTokenExpiry
+}
+
+// RefreshTokenExpiry adalah umur refresh token, token hasil rotasi yang lebih tua dari ini sudah tidak berguna
+func RefreshTokenExpiry() time.Duration {
+	return refreshTokenExpiry
+}
+
+// NewRefreshToken membuat refresh token dalam satu family. Claim jti diisi uuid
+// supaya token hasil rotasi selalu berbeda walaupun dibuat pada detik yang sama
+func NewRefreshToken(id uint64, familyId string) *claims {
 	return &claims{
-		Id: id,
+		Id:     id,
+		Family: familyId,
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewString(),
 			ExpiresAt: time.Now().Add(refreshTokenExpiry).Unix(),
 		},
 	}
 }
 
+// GenerateAccessToken memakai kunci asimetris aktif kalau JWT_SIGNING_KEYS diisi,
+// supaya service lain bisa memverifikasi token lewat jwks tanpa tahu secret
 func (c *claims) GenerateAccessToken() string {
-	parsetoken := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	signedToken, err := parsetoken.SignedString(myAccessToken)
+	var signedToken string
+	var err error
+
+	if activeSigningKey != nil {
+		parsetoken := jwt.NewWithClaims(activeSigningKey.method, c)
+		parsetoken.Header["kid"] = activeSigningKey.kid
+		signedToken, err = parsetoken.SignedString(activeSigningKey.privateKey)
+	} else {
+		parsetoken := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+		signedToken, err = parsetoken.SignedString(myAccessToken)
+	}
 	if err != nil {
 		log.Fatalf("error when generate access token: %s", err.Error())
 	}
@@ -66,30 +104,66 @@ func (c *claims) GenerateRefreshToken() string {
 func VerifyToken(tokenString string) (interface{}, error) {
 	errResponse := errors.New("sign in to proceed")
 
-	token, _ := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errResponse
-		}
-
-		return []byte(myAccessToken), nil
-	})
+	token, err := jwt.Parse(tokenString, accessTokenKey)
+	if err != nil {
+		return nil, errResponse
+	}
 
-	if _, ok := token.Claims.(jwt.MapClaims); !ok && !token.Valid {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
 		return nil, errResponse
 	}
 
-	return token.Claims.(jwt.MapClaims), nil
+	return claims, nil
 }
 
+// GetTokenId mengambil jti, waktu terbit dan waktu kedaluwarsa dari claim access token.
+// Token lama tanpa iat_ms dianggap terbit di akhir detik iat supaya tetap ikut tercabut
+func GetTokenId(userData jwt.MapClaims) (jti string, issuedAt time.Time, expiresAt time.Time) {
+	jti, _ = userData["jti"].(string)
+	iat, _ := userData["iat"].(float64)
+	exp, _ := userData["exp"].(float64)
+
+	issuedAt = time.Unix(int64(iat), 0).Add(time.Second - time.Millisecond)
+	if iatMilli, ok := userData["iat_ms"].(float64); ok {
+		issuedAt = time.UnixMilli(int64(iatMilli))
+	}
+
+	return jti, issuedAt, time.Unix(int64(exp), 0)
+}
+
+// GetSessionId mengambil family refresh token yang menerbitkan access token
+func GetSessionId(userData jwt.MapClaims) string {
+	familyId, _ := userData["Family"].(string)
+	return familyId
+}
+
+// GetActor mengambil identitas user dari claim access token yang disimpan middleware Authentication
+func GetActor(userData jwt.MapClaims) domain.Actor {
+	id, _ := userData["Id"].(float64)
+	role, _ := userData["Role"].(string)
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	return domain.Actor{Id: uint(id), Role: role}
+}
+
+// VerifyRefreshToken hanya menerima refresh token yang punya family,
+// token lama tanpa family harus login ulang
 func VerifyRefreshToken(token string) (*claims, error) {
 	verifyToken, err := jwt.ParseWithClaims(token, &claims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrTokenNotVerified
+		}
+
 		return myRefreshToken, nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if payload, ok := verifyToken.Claims.(*claims); ok && verifyToken.Valid {
+	if payload, ok := verifyToken.Claims.(*claims); ok && verifyToken.Valid && payload.Family != "" {
 		return payload, nil
 	}
 
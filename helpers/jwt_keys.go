@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ariwiraa/my-gram/config"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningMethodEdDSA belum disediakan oleh jwt-go v3, jadi didaftarkan sendiri
+var SigningMethodEdDSA = &signingMethodEd25519{}
+
+// signingKey adalah satu kunci asimetris untuk access token,
+// kid-nya ditulis di header token dan di jwks
+type signingKey struct {
+	kid        string
+	method     jwt.SigningMethod
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+}
+
+var accessSigningKeys, activeSigningKey = loadSigningKeys(config.LoadJwtConfig().GetSigningKeys(), config.LoadJwtConfig().GetSigningKid())
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+type signingMethodEd25519 struct{}
+
+func (m *signingMethodEd25519) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEd25519) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return errors.New("ed25519 signature is invalid")
+	}
+
+	return nil
+}
+
+func (m *signingMethodEd25519) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+// loadSigningKeys membaca private key PEM (PKCS#1 atau PKCS#8). Algoritma ditentukan dari jenis kunci:
+// RSA memakai RS256 dan Ed25519 memakai EdDSA. Kalau tidak ada kunci, access token tetap memakai HS256
+func loadSigningKeys(files []config.SigningKeyFile, signingKid string) (map[string]*signingKey, *signingKey) {
+	keys := make(map[string]*signingKey, len(files))
+	if len(files) == 0 {
+		return keys, nil
+	}
+
+	for _, file := range files {
+		if _, exists := keys[file.Kid]; exists {
+			log.Fatalf("duplicate jwt signing key id %s", file.Kid)
+		}
+
+		key, err := parseSigningKey(file)
+		if err != nil {
+			log.Fatalf("error when load jwt signing key %s: %s", file.Kid, err.Error())
+		}
+
+		keys[file.Kid] = key
+	}
+
+	if signingKid == "" {
+		signingKid = files[0].Kid
+	}
+
+	active, ok := keys[signingKid]
+	if !ok {
+		log.Fatalf("jwt signing key %s is not listed in JWT_SIGNING_KEYS", signingKid)
+	}
+
+	return keys, active
+}
+
+func parseSigningKey(file config.SigningKeyFile) (*signingKey, error) {
+	raw, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("file is not PEM encoded")
+	}
+
+	var privateKey interface{}
+	privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.New("private key must be PKCS#1 RSA or PKCS#8 RSA/Ed25519")
+		}
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &signingKey{kid: file.Kid, method: jwt.SigningMethodRS256, privateKey: key, publicKey: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{kid: file.Kid, method: SigningMethodEdDSA, privateKey: key, publicKey: key.Public()}, nil
+	}
+
+	return nil, errors.New("only RSA and Ed25519 keys are supported")
+}
+
+// JSONWebKey adalah public key dalam format RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeys mengembalikan semua public key access token, termasuk kunci lama yang masih dipakai verifikasi
+func JSONWebKeys() []JSONWebKey {
+	webKeys := make([]JSONWebKey, 0, len(accessSigningKeys))
+
+	for _, key := range accessSigningKeys {
+		webKey := JSONWebKey{Kid: key.kid, Use: "sig", Alg: key.method.Alg()}
+
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			webKey.Kty = "RSA"
+			webKey.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			webKey.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			webKey.Kty = "OKP"
+			webKey.Crv = "Ed25519"
+			webKey.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		webKeys = append(webKeys, webKey)
+	}
+
+	sort.Slice(webKeys, func(i, j int) bool {
+		return webKeys[i].Kid < webKeys[j].Kid
+	})
+
+	return webKeys
+}
+
+// accessTokenKey memilih kunci verifikasi berdasarkan alg dan kid di header token
+func accessTokenKey(t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		// HS256 hanya diterima kalau TOKEN_KEY masih diisi, misalnya selama masa migrasi
+		if len(myAccessToken) == 0 {
+			return nil, ErrTokenNotVerified
+		}
+		return myAccessToken, nil
+	case *jwt.SigningMethodRSA, *signingMethodEd25519:
+		kid, _ := t.Header["kid"].(string)
+		key, ok := accessSigningKeys[kid]
+		if !ok || key.method.Alg() != t.Method.Alg() {
+			return nil, ErrTokenNotVerified
+		}
+		return key.publicKey, nil
+	}
+
+	return nil, ErrTokenNotVerified
+}
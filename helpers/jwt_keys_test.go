@@ -0,0 +1,217 @@
+package helpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariwiraa/my-gram/config"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// writeKeyFile menyimpan kunci dalam format PEM di folder sementara test
+func writeKeyFile(t *testing.T, name, blockType string, der []byte) config.SigningKeyFile {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile() error %v", err)
+	}
+
+	return config.SigningKeyFile{Kid: name, Path: path}
+}
+
+// testSigningKeys membuat satu kunci RSA (PKCS#1) dan satu kunci Ed25519 (PKCS#8),
+// lalu memasangnya sebagai kunci access token selama test berjalan
+func testSigningKeys(t *testing.T, activeKid string) (*rsa.PrivateKey, ed25519.PrivateKey) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error %v", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error %v", err)
+	}
+
+	edDer, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error %v", err)
+	}
+
+	files := []config.SigningKeyFile{
+		writeKeyFile(t, "rsa-1", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+		writeKeyFile(t, "ed-1", "PRIVATE KEY", edDer),
+	}
+
+	previousKeys, previousActive, previousSecret, previousExpiry := accessSigningKeys, activeSigningKey, myAccessToken, accessTokenExpiry
+	t.Cleanup(func() {
+		accessSigningKeys, activeSigningKey, myAccessToken, accessTokenExpiry = previousKeys, previousActive, previousSecret, previousExpiry
+	})
+
+	// token harus masih berlaku supaya penolakan di test memang karena kuncinya
+	accessSigningKeys, activeSigningKey = loadSigningKeys(files, activeKid)
+	myAccessToken = nil
+	accessTokenExpiry = time.Minute
+
+	return rsaKey, edKey
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		kid string
+		alg string
+	}{
+		{kid: "rsa-1", alg: "RS256"},
+		{kid: "ed-1", alg: "EdDSA"},
+	} {
+		t.Run(tt.alg, func(t *testing.T) {
+			testSigningKeys(t, tt.kid)
+
+			tokenString := NewAccessToken(42, "admin", "family").GenerateAccessToken()
+
+			token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified() error %v", err)
+			}
+			if token.Header["alg"] != tt.alg || token.Header["kid"] != tt.kid {
+				t.Fatalf("header = %v, want alg %s and kid %s", token.Header, tt.alg, tt.kid)
+			}
+
+			verified, err := VerifyToken(tokenString)
+			if err != nil {
+				t.Fatalf("VerifyToken() error %v", err)
+			}
+			if id := verified.(jwt.MapClaims)["Id"]; id != float64(42) {
+				t.Fatalf("VerifyToken() Id = %v, want 42", id)
+			}
+		})
+	}
+}
+
+func TestVerifyTokenRejectsUnknownKey(t *testing.T) {
+	rsaKey, edKey := testSigningKeys(t, "rsa-1")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error %v", err)
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, NewAccessToken(42, "user", "family"))
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		tokenString, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error %v", err)
+		}
+
+		return tokenString
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "kid tidak dikenal", token: sign(jwt.SigningMethodRS256, "rsa-2", rsaKey)},
+		{name: "tanpa kid", token: sign(jwt.SigningMethodRS256, "", rsaKey)},
+		{name: "alg tidak sesuai kid", token: sign(SigningMethodEdDSA, "rsa-1", edKey)},
+		{name: "ditandatangani kunci lain", token: sign(jwt.SigningMethodRS256, "rsa-1", otherKey)},
+		{name: "HS256 tanpa TOKEN_KEY", token: sign(jwt.SigningMethodHS256, "", []byte{})},
+		{name: "alg none", token: sign(jwt.SigningMethodNone, "rsa-1", jwt.UnsafeAllowNoneSignatureType)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyToken(tt.token); err == nil {
+				t.Fatalf("VerifyToken() accepted %s", tt.name)
+			}
+		})
+	}
+
+	// HS256 tetap diterima selama masa migrasi kalau TOKEN_KEY diisi
+	myAccessToken = []byte("secret")
+	if _, err := VerifyToken(sign(jwt.SigningMethodHS256, "", myAccessToken)); err != nil {
+		t.Fatalf("VerifyToken() rejected HS256 with TOKEN_KEY: %v", err)
+	}
+}
+
+func TestParseSigningKeyRejectsUnsupportedKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() error %v", err)
+	}
+
+	ecDer, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error %v", err)
+	}
+
+	notPem := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPem, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error %v", err)
+	}
+
+	for _, file := range []config.SigningKeyFile{
+		writeKeyFile(t, "ec-1", "PRIVATE KEY", ecDer),
+		{Kid: "plain", Path: notPem},
+	} {
+		if _, err := parseSigningKey(file); err == nil {
+			t.Fatalf("parseSigningKey(%s) accepted an unsupported key", file.Kid)
+		}
+	}
+}
+
+func TestJSONWebKeys(t *testing.T) {
+	rsaKey, _ := testSigningKeys(t, "rsa-1")
+
+	// contoh kunci Ed25519 dari RFC 8037 lampiran A.1 dan A.2
+	seed, _ := base64.RawURLEncoding.DecodeString("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")
+	rfcKey := ed25519.NewKeyFromSeed(seed)
+	accessSigningKeys["ed-1"] = &signingKey{kid: "ed-1", method: SigningMethodEdDSA, privateKey: rfcKey, publicKey: rfcKey.Public()}
+
+	webKeys := JSONWebKeys()
+	if len(webKeys) != 2 || webKeys[0].Kid != "ed-1" || webKeys[1].Kid != "rsa-1" {
+		t.Fatalf("JSONWebKeys() = %+v, want ed-1 and rsa-1 sorted by kid", webKeys)
+	}
+
+	ed := webKeys[0]
+	if ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != "EdDSA" || ed.Use != "sig" {
+		t.Fatalf("Ed25519 key = %+v", ed)
+	}
+	if ed.X != "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo" {
+		t.Fatalf("Ed25519 x = %s, want value from RFC 8037", ed.X)
+	}
+
+	rsaWebKey := webKeys[1]
+	if rsaWebKey.Kty != "RSA" || rsaWebKey.Alg != "RS256" || rsaWebKey.Use != "sig" {
+		t.Fatalf("RSA key = %+v", rsaWebKey)
+	}
+	// eksponen 65537 ditulis tanpa byte nol di depan
+	if rsaWebKey.E != "AQAB" {
+		t.Fatalf("RSA e = %s, want AQAB", rsaWebKey.E)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(rsaWebKey.N)
+	if err != nil {
+		t.Fatalf("RSA n is not base64url: %v", err)
+	}
+	if new(big.Int).SetBytes(n).Cmp(rsaKey.N) != 0 || n[0] == 0 {
+		t.Fatalf("RSA n does not match the public key modulus")
+	}
+}
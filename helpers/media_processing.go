@@ -0,0 +1,175 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/gif"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// ProcessMedia menjalankan pipeline sesuai content type hasil DetectMediaType
+func ProcessMedia(ctx context.Context, content []byte, contentType string) (*ProcessedMedia, error) {
+	switch contentType {
+	case "image/jpeg", "image/png":
+		return ProcessImage(content)
+	case "image/gif":
+		return processGif(content)
+	case "image/webp":
+		return processWebp(content)
+	case "video/mp4":
+		return processVideo(ctx, content)
+	}
+
+	return nil, ErrFileNotSupported
+}
+
+// processGif menyimpan gif apa adanya supaya animasinya tetap jalan,
+// medium dan thumbnail dibuat dari frame pertama. Jumlah piksel seluruh frame
+// dicek dari struktur block dulu, yang di-decode hanya frame pertama
+func processGif(content []byte) (*ProcessedMedia, error) {
+	info, err := parseGif(content)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig := image.Config{Width: info.Width, Height: info.Height}
+	err = isAnimatedDimensionValid(imageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Frames == 0 || info.Width == 0 || info.Height == 0 {
+		return nil, ErrFileNotSupported
+	}
+
+	firstFrame, err := gif.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, ErrFileNotSupported
+	}
+
+	processed := &ProcessedMedia{
+		MediaType: domain.MediaTypeImage,
+		Full:      MediaRendition{Content: content, ContentType: "image/gif", Width: imageConfig.Width, Height: imageConfig.Height},
+	}
+
+	if info.Frames > 1 {
+		processed.MediaType = domain.MediaTypeAnimation
+		processed.Duration = info.Duration
+	}
+
+	err = IsDurationValid(processed.Duration, "image/gif")
+	if err != nil {
+		return nil, err
+	}
+
+	// frame pertama bisa lebih kecil dari canvas, jadi digambar ke canvas penuh dulu
+	canvas := image.NewNRGBA(image.Rect(0, 0, imageConfig.Width, imageConfig.Height))
+	draw.Draw(canvas, firstFrame.Bounds(), firstFrame, firstFrame.Bounds().Min, draw.Over)
+
+	analyzeImage(processed, canvas)
+	processed.Medium, processed.Thumbnail, err = previewRenditions(canvas, "png")
+	if err != nil {
+		return nil, err
+	}
+
+	return processed, nil
+}
+
+// processWebp membuang metadata webp. Decoder webp belum mendukung animasi,
+// jadi medium dan thumbnail hanya dibuat untuk webp statis
+func processWebp(content []byte) (*ProcessedMedia, error) {
+	info, err := parseWebp(content)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig, err := webp.DecodeConfig(bytes.NewReader(info.Stripped))
+	if err != nil {
+		return nil, ErrFileNotSupported
+	}
+
+	err = isAnimatedDimensionValid(imageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	processed := &ProcessedMedia{
+		MediaType: domain.MediaTypeImage,
+		Duration:  info.Duration,
+		Full:      MediaRendition{Content: info.Stripped, ContentType: "image/webp", Width: imageConfig.Width, Height: imageConfig.Height},
+	}
+
+	if info.Animated {
+		processed.MediaType = domain.MediaTypeAnimation
+
+		err = IsDurationValid(processed.Duration, "image/webp")
+		if err != nil {
+			return nil, err
+		}
+
+		return processed, nil
+	}
+
+	img, err := webp.Decode(bytes.NewReader(info.Stripped))
+	if err != nil {
+		return nil, ErrFileNotSupported
+	}
+
+	analyzeImage(processed, img)
+	processed.Medium, processed.Thumbnail, err = previewRenditions(img, "png")
+	if err != nil {
+		return nil, err
+	}
+
+	return processed, nil
+}
+
+// processVideo memeriksa durasi mp4 lalu membuat poster beserta medium dan thumbnail-nya
+func processVideo(ctx context.Context, content []byte) (*ProcessedMedia, error) {
+	duration, err := Mp4Duration(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// durasi dicek sebelum ffmpeg dijalankan supaya video yang ditolak tidak sempat diproses
+	err = IsDurationValid(duration, "video/mp4")
+	if err != nil {
+		return nil, err
+	}
+
+	posterFrame, err := ExtractPosterFrame(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	poster, err := ProcessImage(posterFrame)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProcessedMedia{
+		MediaType: domain.MediaTypeVideo,
+		Duration:  duration,
+		Full:      MediaRendition{Content: content, ContentType: "video/mp4", Width: poster.Full.Width, Height: poster.Full.Height},
+		Poster:    &poster.Full,
+		Medium:    poster.Medium,
+		Thumbnail: poster.Thumbnail,
+
+		PerceptualHash: poster.PerceptualHash,
+		BlurHash:       poster.BlurHash,
+		DominantColor:  poster.DominantColor,
+	}, nil
+}
+
+// isAnimatedDimensionValid membatasi dimensi gif dan webp yang disimpan tanpa di-resize
+func isAnimatedDimensionValid(imageConfig image.Config) error {
+	if imageConfig.Width > IMAGE_FULL_DIMENSION || imageConfig.Height > IMAGE_FULL_DIMENSION {
+		return ErrImageTooLarge
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// MAX_MENTIONS membatasi jumlah username berbeda yang di-resolve dari satu text
+	MAX_MENTIONS = 20
+
+	mentionMinLength = 3
+)
+
+// MentionToken adalah satu @username di dalam text. Offset dan Length dihitung dalam
+// UTF-16 code unit, sama dengan index string di JavaScript, Android dan iOS, dan mencakup karakter @.
+// Emoji di luar BMP terhitung dua code unit
+type MentionToken struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+// ParseMentions mencari @username di text. @ hanya dihitung kalau berada di awal text
+// atau setelah karakter yang bukan bagian username, jadi alamat email tidak ikut terbaca.
+// Titik di akhir username dianggap tanda baca
+func ParseMentions(text string) []MentionToken {
+	var tokens []MentionToken
+	usernames := make(map[string]bool)
+
+	runes := []rune(text)
+	positions := utf16Positions(runes)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && (isUsernameRune(runes[i-1]) || runes[i-1] == '@')) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isUsernameRune(runes[end]) {
+			end++
+		}
+		for end > i+1 && runes[end-1] == '.' {
+			end--
+		}
+
+		username := string(runes[i+1 : end])
+		if utf8.RuneCountInString(username) < mentionMinLength {
+			continue
+		}
+
+		if !usernames[username] {
+			if len(usernames) == MAX_MENTIONS {
+				continue
+			}
+			usernames[username] = true
+		}
+
+		tokens = append(tokens, MentionToken{Username: username, Offset: positions[i], Length: positions[end] - positions[i]})
+		i = end - 1
+	}
+
+	return tokens
+}
+
+// utf16Positions mengembalikan posisi UTF-16 setiap rune, ditambah panjang total text di index terakhir
+func utf16Positions(runes []rune) []int {
+	positions := make([]int, len(runes)+1)
+	// byte yang bukan UTF-8 valid sudah menjadi U+FFFD di []rune, jadi RuneLen selalu 1 atau 2
+	for i, r := range runes {
+		positions[i+1] = positions[i] + utf16.RuneLen(r)
+	}
+
+	return positions
+}
+
+func isUsernameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	var manyMentions []string
+	for i := 0; i <= MAX_MENTIONS; i++ {
+		manyMentions = append(manyMentions, fmt.Sprintf("@user%02d", i))
+	}
+	manyMentions = append(manyMentions, "@user00")
+
+	var wantManyMentions []MentionToken
+	for i := 0; i < MAX_MENTIONS; i++ {
+		wantManyMentions = append(wantManyMentions, MentionToken{Username: fmt.Sprintf("user%02d", i), Offset: i * 8, Length: 7})
+	}
+	wantManyMentions = append(wantManyMentions, MentionToken{Username: "user00", Offset: (MAX_MENTIONS + 1) * 8, Length: 7})
+
+	tests := []struct {
+		name string
+		text string
+		want []MentionToken
+	}{
+		{
+			name: "di awal text",
+			text: "@alice hai",
+			want: []MentionToken{{Username: "alice", Offset: 0, Length: 6}},
+		},
+		{
+			name: "alamat email tidak dihitung",
+			text: "kirim ke bob@example.com",
+			want: nil,
+		},
+		{
+			name: "titik di akhir adalah tanda baca",
+			text: "halo @bob.smith.",
+			want: []MentionToken{{Username: "bob.smith", Offset: 5, Length: 10}},
+		},
+		{
+			name: "username terlalu pendek",
+			text: "@ab dan @@alice",
+			want: nil,
+		},
+		{
+			name: "emoji dihitung dua code unit",
+			text: "😀 @alice 🎉 @bob_1",
+			want: []MentionToken{
+				{Username: "alice", Offset: 3, Length: 6},
+				{Username: "bob_1", Offset: 13, Length: 6},
+			},
+		},
+		{
+			name: "huruf non latin",
+			text: "héllo @josé",
+			want: []MentionToken{{Username: "josé", Offset: 6, Length: 5}},
+		},
+		{
+			name: "username yang sama tetap dicatat setiap kemunculannya",
+			text: "@alice, @alice!",
+			want: []MentionToken{
+				{Username: "alice", Offset: 0, Length: 6},
+				{Username: "alice", Offset: 8, Length: 6},
+			},
+		},
+		{
+			name: "username berbeda dibatasi",
+			text: strings.Join(manyMentions, " "),
+			want: wantManyMentions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseMentions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
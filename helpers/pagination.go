@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT = 10
+	MAX_PAGE_LIMIT     = 50
+)
+
+// Cursor menunjuk ke baris terakhir yang sudah dikirim ke client,
+// urutannya created_at DESC lalu id DESC. Score hanya terisi untuk
+// urutan yang diawali kolom lain, misalnya comment terpopuler
+type Cursor struct {
+	CreatedAt time.Time
+	Id        string
+	Score     *int64
+}
+
+type PageRequest struct {
+	Cursor *Cursor
+	Limit  int
+}
+
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.Id
+	if c.Score != nil {
+		raw += "|" + strconv.FormatInt(*c.Score, 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// UintId dipakai untuk tabel yang primary key-nya bukan uuid (comments, follows, users),
+// cursor-nya harus dibuat lewat NewNumericPageRequest supaya id sudah pasti angka
+func (c Cursor) UintId() uint {
+	id, _ := strconv.ParseUint(c.Id, 10, 64)
+	return uint(id)
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, ErrCursorInvalid
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrCursorInvalid
+	}
+
+	cursor := &Cursor{CreatedAt: parsedTime, Id: id}
+
+	id, score, found := strings.Cut(id, "|")
+	if found {
+		parsedScore, err := strconv.ParseInt(score, 10, 64)
+		if err != nil || id == "" {
+			return nil, ErrCursorInvalid
+		}
+		cursor.Id = id
+		cursor.Score = &parsedScore
+	}
+
+	return cursor, nil
+}
+
+func NewPageRequest(cursor, limit string) (PageRequest, error) {
+	page := PageRequest{Limit: DEFAULT_PAGE_LIMIT}
+
+	if limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 {
+			return page, ErrPageLimitInvalid
+		}
+
+		if parsedLimit > MAX_PAGE_LIMIT {
+			parsedLimit = MAX_PAGE_LIMIT
+		}
+		page.Limit = parsedLimit
+	}
+
+	if cursor != "" {
+		decodedCursor, err := DecodeCursor(cursor)
+		if err != nil {
+			return page, err
+		}
+		page.Cursor = decodedCursor
+	}
+
+	return page, nil
+}
+
+// NewNumericPageRequest sama seperti NewPageRequest tapi menolak cursor yang id-nya bukan angka,
+// dipakai untuk list yang primary key-nya numerik
+func NewNumericPageRequest(cursor, limit string) (PageRequest, error) {
+	page, err := NewPageRequest(cursor, limit)
+	if err != nil {
+		return page, err
+	}
+
+	if page.Cursor != nil {
+		if _, err := strconv.ParseUint(page.Cursor.Id, 10, 64); err != nil {
+			return page, ErrCursorInvalid
+		}
+	}
+
+	return page, nil
+}
+
+// Paginate memotong hasil query yang diambil dengan limit+1 baris
+// dan membuat cursor untuk halaman berikutnya
+func Paginate[T any](items []T, limit int, cursorOf func(T) Cursor) ([]T, PageInfo) {
+	if len(items) <= limit {
+		return items, PageInfo{HasMore: false}
+	}
+
+	items = items[:limit]
+
+	return items, PageInfo{
+		NextCursor: cursorOf(items[len(items)-1]).Encode(),
+		HasMore:    true,
+	}
+}
@@ -0,0 +1,163 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func encodeRaw(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func TestDecodeCursor(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.UTC)
+	score := int64(42)
+	negativeScore := int64(-7)
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    *Cursor
+		wantErr error
+	}{
+		{
+			name:    "round trip tanpa score",
+			encoded: Cursor{CreatedAt: createdAt, Id: "15"}.Encode(),
+			want:    &Cursor{CreatedAt: createdAt, Id: "15"},
+		},
+		{
+			name:    "round trip dengan score",
+			encoded: Cursor{CreatedAt: createdAt, Id: "15", Score: &score}.Encode(),
+			want:    &Cursor{CreatedAt: createdAt, Id: "15", Score: &score},
+		},
+		{
+			name:    "score negatif",
+			encoded: Cursor{CreatedAt: createdAt, Id: "15", Score: &negativeScore}.Encode(),
+			want:    &Cursor{CreatedAt: createdAt, Id: "15", Score: &negativeScore},
+		},
+		{
+			name:    "id uuid",
+			encoded: Cursor{CreatedAt: createdAt, Id: "4b1c2a4e-5d57-4c4f-8f3b-0e6f1f3d9a10"}.Encode(),
+			want:    &Cursor{CreatedAt: createdAt, Id: "4b1c2a4e-5d57-4c4f-8f3b-0e6f1f3d9a10"},
+		},
+		{
+			name:    "bukan base64",
+			encoded: "%%%",
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "tanpa pemisah",
+			encoded: encodeRaw("2024-03-01T10:30:00Z"),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "id kosong",
+			encoded: encodeRaw("2024-03-01T10:30:00Z|"),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "waktu tidak valid",
+			encoded: encodeRaw("kemarin|15"),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "score bukan angka",
+			encoded: encodeRaw("2024-03-01T10:30:00Z|15|banyak"),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "id kosong sebelum score",
+			encoded: encodeRaw("2024-03-01T10:30:00Z||3"),
+			wantErr: ErrCursorInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.encoded)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DecodeCursor() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor() unexpected error %v", err)
+			}
+
+			if !got.CreatedAt.Equal(tt.want.CreatedAt) || got.Id != tt.want.Id {
+				t.Fatalf("DecodeCursor() = %+v, want %+v", got, tt.want)
+			}
+			if (got.Score == nil) != (tt.want.Score == nil) {
+				t.Fatalf("DecodeCursor() score = %v, want %v", got.Score, tt.want.Score)
+			}
+			if got.Score != nil && *got.Score != *tt.want.Score {
+				t.Fatalf("DecodeCursor() score = %d, want %d", *got.Score, *tt.want.Score)
+			}
+		})
+	}
+}
+
+func TestNewNumericPageRequest(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		cursor  string
+		limit   string
+		wantId  uint
+		wantErr error
+	}{
+		{
+			name:   "tanpa cursor",
+			cursor: "",
+		},
+		{
+			name:   "id numerik",
+			cursor: Cursor{CreatedAt: createdAt, Id: "27"}.Encode(),
+			wantId: 27,
+		},
+		{
+			name:    "id uuid ditolak",
+			cursor:  Cursor{CreatedAt: createdAt, Id: "4b1c2a4e-5d57-4c4f-8f3b-0e6f1f3d9a10"}.Encode(),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "id negatif ditolak",
+			cursor:  Cursor{CreatedAt: createdAt, Id: "-1"}.Encode(),
+			wantErr: ErrCursorInvalid,
+		},
+		{
+			name:    "limit tidak valid",
+			limit:   "0",
+			wantErr: ErrPageLimitInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := NewNumericPageRequest(tt.cursor, tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewNumericPageRequest() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewNumericPageRequest() unexpected error %v", err)
+			}
+
+			if tt.cursor == "" {
+				if page.Cursor != nil {
+					t.Fatalf("NewNumericPageRequest() cursor = %+v, want nil", page.Cursor)
+				}
+				return
+			}
+			if page.Cursor.UintId() != tt.wantId {
+				t.Fatalf("UintId() = %d, want %d", page.Cursor.UintId(), tt.wantId)
+			}
+		})
+	}
+}
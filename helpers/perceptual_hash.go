@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// PERCEPTUAL_HASH_MAX_DISTANCE adalah jarak hamming maksimal dua hash yang dianggap gambar yang sama
+const PERCEPTUAL_HASH_MAX_DISTANCE = 5
+
+// DHash menghitung difference hash 64 bit. Gambar dikecilkan menjadi 9x8 grayscale,
+// lalu setiap bit menandakan apakah sebuah pixel lebih terang dari pixel di kanannya.
+// Hasilnya tetap sama walaupun gambar di-resize atau dikompres ulang
+func DHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
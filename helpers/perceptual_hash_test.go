@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/bits"
+	"testing"
+)
+
+// gradientImage membuat gambar grayscale yang kecerahannya hanya bergantung pada posisi x
+func gradientImage(width, height int, shade func(x int) uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: shade(x)})
+		}
+	}
+
+	return img
+}
+
+// patternImage membuat gambar kotak-kotak dengan tingkat kecerahan berbeda di setiap kotak
+func patternImage(width, height int, seed uint8) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cell := uint8(x*9/width+(y*8/height)*9) * 37
+			img.Set(x, y, color.RGBA{R: cell + seed, G: cell ^ seed, B: cell, A: 255})
+		}
+	}
+
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		want uint64
+	}{
+		{
+			name: "gambar polos",
+			img:  gradientImage(90, 80, func(x int) uint8 { return 128 }),
+			want: 0,
+		},
+		{
+			name: "semakin terang ke kanan",
+			img:  gradientImage(90, 80, func(x int) uint8 { return uint8(x * 255 / 89) }),
+			want: 0,
+		},
+		{
+			name: "semakin gelap ke kanan",
+			img:  gradientImage(90, 80, func(x int) uint8 { return uint8(255 - x*255/89) }),
+			want: ^uint64(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DHash(tt.img); got != tt.want {
+				t.Fatalf("DHash() = %016x, want %016x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDHashDistance(t *testing.T) {
+	original := patternImage(900, 800, 0)
+
+	recompressed := bytes.NewBuffer(nil)
+	if err := jpeg.Encode(recompressed, original, &jpeg.Options{Quality: 40}); err != nil {
+		t.Fatalf("jpeg.Encode() error %v", err)
+	}
+	decoded, err := jpeg.Decode(recompressed)
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		img     image.Image
+		similar bool
+	}{
+		{name: "di-resize", img: patternImage(180, 160, 0), similar: true},
+		{name: "dikompres ulang", img: decoded, similar: true},
+		{name: "gambar lain", img: patternImage(900, 800, 101), similar: false},
+	}
+
+	hash := DHash(original)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance := bits.OnesCount64(hash ^ DHash(tt.img))
+			if (distance <= PERCEPTUAL_HASH_MAX_DISTANCE) != tt.similar {
+				t.Fatalf("distance = %d, want similar %t", distance, tt.similar)
+			}
+		})
+	}
+}
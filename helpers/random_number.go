@@ -1,10 +1,16 @@
 package helpers
 
 import (
+	cryptorand "crypto/rand"
+	"fmt"
+	"math/big"
 	"math/rand"
 	"time"
 )
 
+// RESET_CODE_DIGITS adalah panjang kode reset password
+const RESET_CODE_DIGITS = 8
+
 func GenerateRandomOTP() int {
 	source := rand.NewSource(time.Now().UnixNano())
 	random := rand.New(source)
@@ -16,3 +22,14 @@ func GenerateRandomOTP() int {
 
 	return otp
 }
+
+// GenerateResetCode membuat kode reset password dari crypto/rand supaya tidak bisa ditebak dari waktu pembuatannya
+func GenerateResetCode() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(RESET_CODE_DIGITS), nil)
+	code, err := cryptorand.Int(cryptorand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", RESET_CODE_DIGITS, code), nil
+}
@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateResetCode(t *testing.T) {
+	codes := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := GenerateResetCode()
+		if err != nil {
+			t.Fatalf("GenerateResetCode() error %v", err)
+		}
+		if len(code) != RESET_CODE_DIGITS || strings.Trim(code, "0123456789") != "" {
+			t.Fatalf("GenerateResetCode() = %q, want %d digits", code, RESET_CODE_DIGITS)
+		}
+		codes[code] = true
+	}
+
+	// 100 kode dari 10^8 kemungkinan hampir pasti berbeda semua
+	if len(codes) < 99 {
+		t.Fatalf("GenerateResetCode() produced %d unique codes out of 100", len(codes))
+	}
+}
@@ -9,6 +9,7 @@ type Response struct {
 	Success   bool        `json:"success"`
 	Message   string      `json:"message"`
 	Payload   interface{} `json:"payload,omitempty"`
+	Meta      *PageInfo   `json:"meta,omitempty"`
 	Error     string      `json:"error,omitempty"`
 	ErrorCode string      `json:"error_code,omitempty"`
 }
@@ -44,6 +45,13 @@ func WithPayload(payload interface{}) func(*Response) *Response {
 	}
 }
 
+func WithPagination(pageInfo PageInfo) func(*Response) *Response {
+	return func(r *Response) *Response {
+		r.Meta = &pageInfo
+		return r
+	}
+}
+
 func WithError(err error) func(*Response) *Response {
 	return func(r *Response) *Response {
 		r.Success = false
@@ -36,6 +36,8 @@ func ErrorFieldRequired(field string) error {
 		return ErrUsernameRequired
 	case "Message":
 		return ErrCommentMessageRequired
+	case "Code":
+		return ErrResetCodeRequired
 	}
 
 	return ErrBadRequest
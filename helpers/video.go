@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	// FFMPEG_BINARY dipakai untuk mengambil poster frame, harus tersedia di PATH
+	FFMPEG_BINARY = "ffmpeg"
+
+	posterFrameTimeout = 30 * time.Second
+)
+
+// Mp4Duration membaca durasi video dari box moov/mvhd
+func Mp4Duration(content []byte) (time.Duration, error) {
+	moov, ok := findMp4Box(content, "moov")
+	if !ok {
+		return 0, ErrFileNotSupported
+	}
+
+	mvhd, ok := findMp4Box(moov, "mvhd")
+	if !ok || len(mvhd) < 20 {
+		return 0, ErrFileNotSupported
+	}
+
+	var timescale, duration uint64
+	// versi 1 memakai field 64 bit untuk waktu dan durasi
+	if mvhd[0] == 1 {
+		if len(mvhd) < 32 {
+			return 0, ErrFileNotSupported
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	}
+
+	if timescale == 0 {
+		return 0, ErrFileNotSupported
+	}
+
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// findMp4Box mencari box dengan tipe boxType di satu level dan mengembalikan isinya
+func findMp4Box(content []byte, boxType string) ([]byte, bool) {
+	offset := 0
+	for offset+8 <= len(content) {
+		size := uint64(binary.BigEndian.Uint32(content[offset : offset+4]))
+		headerSize := uint64(8)
+
+		switch size {
+		case 0:
+			// box terakhir, panjangnya sampai akhir file
+			size = uint64(len(content) - offset)
+		case 1:
+			if offset+16 > len(content) {
+				return nil, false
+			}
+			size = binary.BigEndian.Uint64(content[offset+8 : offset+16])
+			headerSize = 16
+		}
+
+		if size < headerSize || uint64(offset)+size > uint64(len(content)) {
+			return nil, false
+		}
+
+		if string(content[offset+4:offset+8]) == boxType {
+			return content[uint64(offset)+headerSize : uint64(offset)+size], true
+		}
+
+		offset += int(size)
+	}
+
+	return nil, false
+}
+
+// ExtractPosterFrame mengambil frame pertama video sebagai PNG menggunakan ffmpeg.
+// File video ditulis ke file sementara karena mp4 dengan moov di akhir tidak bisa dibaca dari pipe
+func ExtractPosterFrame(ctx context.Context, content []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, posterFrameTimeout)
+	defer cancel()
+
+	tempFile, err := os.CreateTemp("", "mygram-video-*.mp4")
+	if err != nil {
+		log.Printf("[ExtractPosterFrame, CreateTemp] with error detail %v", err.Error())
+		return nil, ErrVideoProcessing
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(content)
+	tempFile.Close()
+	if err != nil {
+		log.Printf("[ExtractPosterFrame, Write] with error detail %v", err.Error())
+		return nil, ErrVideoProcessing
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	cmd := exec.CommandContext(ctx, FFMPEG_BINARY,
+		"-v", "error",
+		"-i", tempFile.Name(),
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"pipe:1",
+	)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	if err != nil || stdout.Len() == 0 {
+		log.Printf("[ExtractPosterFrame, Run] with error detail %v %s", err, stderr.String())
+		return nil, ErrVideoProcessing
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mp4Box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	buf := bytes.NewBuffer(nil)
+	binary.Write(buf, binary.BigEndian, uint32(8+len(body)))
+	buf.WriteString(boxType)
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+func mvhdV0(timescale, duration uint32) []byte {
+	payload := make([]byte, 100)
+	binary.BigEndian.PutUint32(payload[12:16], timescale)
+	binary.BigEndian.PutUint32(payload[16:20], duration)
+	return mp4Box("mvhd", payload)
+}
+
+func mvhdV1(timescale uint32, duration uint64) []byte {
+	payload := make([]byte, 112)
+	payload[0] = 1
+	binary.BigEndian.PutUint32(payload[20:24], timescale)
+	binary.BigEndian.PutUint64(payload[24:32], duration)
+	return mp4Box("mvhd", payload)
+}
+
+func TestMp4Duration(t *testing.T) {
+	ftyp := mp4Box("ftyp", []byte("isom0000"))
+	mdat := mp4Box("mdat", make([]byte, 32))
+
+	largeMdat := bytes.NewBuffer(nil)
+	binary.Write(largeMdat, binary.BigEndian, uint32(1))
+	largeMdat.WriteString("mdat")
+	binary.Write(largeMdat, binary.BigEndian, uint64(16+8))
+	largeMdat.Write(make([]byte, 8))
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    time.Duration
+		wantErr error
+	}{
+		{
+			name:    "mvhd versi 0",
+			content: bytes.Join([][]byte{ftyp, mp4Box("moov", mvhdV0(1000, 15500)), mdat}, nil),
+			want:    15500 * time.Millisecond,
+		},
+		{
+			name:    "moov di akhir file",
+			content: bytes.Join([][]byte{ftyp, mdat, mp4Box("moov", mvhdV0(600, 1800))}, nil),
+			want:    3 * time.Second,
+		},
+		{
+			name:    "mvhd versi 1",
+			content: bytes.Join([][]byte{ftyp, mp4Box("moov", mvhdV1(90000, 90000*42))}, nil),
+			want:    42 * time.Second,
+		},
+		{
+			name:    "box dengan ukuran 64 bit",
+			content: bytes.Join([][]byte{ftyp, largeMdat.Bytes(), mp4Box("moov", mvhdV0(1, 7))}, nil),
+			want:    7 * time.Second,
+		},
+		{
+			name:    "tanpa moov",
+			content: bytes.Join([][]byte{ftyp, mdat}, nil),
+			wantErr: ErrFileNotSupported,
+		},
+		{
+			name:    "timescale nol",
+			content: mp4Box("moov", mvhdV0(0, 100)),
+			wantErr: ErrFileNotSupported,
+		},
+		{
+			name:    "ukuran box melewati file",
+			content: ftyp[:len(ftyp)-2],
+			wantErr: ErrFileNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Mp4Duration(tt.content)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Mp4Duration() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Mp4Duration() unexpected error %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Mp4Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+const (
+	webpFlagAnimation = 0x02
+	webpFlagXMP       = 0x04
+	webpFlagEXIF      = 0x08
+)
+
+// webpInfo adalah hasil membaca container RIFF sebuah webp
+type webpInfo struct {
+	Animated bool
+	Duration time.Duration
+	// Stripped adalah file webp yang sama tanpa chunk EXIF dan XMP
+	Stripped []byte
+}
+
+// parseWebp membaca chunk RIFF webp: menghitung durasi animasi dari chunk ANMF
+// dan membuang chunk metadata supaya lokasi atau data kamera tidak ikut tersimpan
+func parseWebp(content []byte) (*webpInfo, error) {
+	if len(content) < 12 || string(content[:4]) != "RIFF" || string(content[8:12]) != "WEBP" {
+		return nil, ErrFileNotSupported
+	}
+
+	info := new(webpInfo)
+	chunks := bytes.NewBuffer(nil)
+
+	offset := 12
+	for offset+8 <= len(content) {
+		fourCC := string(content[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(content[offset+4 : offset+8]))
+
+		// ukuran chunk ganjil diberi padding satu byte
+		end := offset + 8 + size + size%2
+		if end > len(content) {
+			return nil, ErrFileNotSupported
+		}
+
+		chunk := content[offset:end]
+		payload := content[offset+8 : offset+8+size]
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+			offset = end
+			continue
+		case "VP8X":
+			if len(payload) < 1 {
+				return nil, ErrFileNotSupported
+			}
+			info.Animated = payload[0]&webpFlagAnimation != 0
+
+			// flag metadata dimatikan karena chunk-nya sudah dibuang
+			chunk = append([]byte(nil), chunk...)
+			chunk[8] &^= webpFlagEXIF | webpFlagXMP
+		case "ANMF":
+			if len(payload) < 16 {
+				return nil, ErrFileNotSupported
+			}
+			// durasi frame dalam milidetik, 24 bit little endian
+			frameDuration := int(payload[12]) | int(payload[13])<<8 | int(payload[14])<<16
+			info.Duration += time.Duration(frameDuration) * time.Millisecond
+		}
+
+		chunks.Write(chunk)
+		offset = end
+	}
+
+	stripped := bytes.NewBuffer(make([]byte, 0, chunks.Len()+12))
+	stripped.WriteString("RIFF")
+	binary.Write(stripped, binary.LittleEndian, uint32(chunks.Len()+4))
+	stripped.WriteString("WEBP")
+	stripped.Write(chunks.Bytes())
+
+	info.Stripped = stripped.Bytes()
+
+	return info, nil
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/ariwiraa/my-gram/config"
 	"github.com/ariwiraa/my-gram/handler"
+	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	repositoryImpl "github.com/ariwiraa/my-gram/repository/impl"
 	"github.com/ariwiraa/my-gram/routes"
+	"github.com/ariwiraa/my-gram/usecase"
 	usecaseImpl "github.com/ariwiraa/my-gram/usecase/impl"
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	_ "github.com/joho/godotenv/autoload"
@@ -25,22 +29,50 @@ func main() {
 		panic(err)
 	}
 
-	cloudinary, err := config.ConnectCloudinary(cfg)
+	storageProvider, err := newStorageProvider(cfg)
 	if err != nil {
 		panic(err)
 	}
 
-	router := newApp(db, redis, cloudinary)
+	router := newApp(cfg, db, redis, storageProvider)
+
+	// driver local menyajikan file langsung dari disk
+	if cfg.Storage.Driver == config.STORAGE_LOCAL {
+		router.Static(config.LocalStorageRoute, cfg.Storage.LocalDir)
+	}
 
 	router.Run(":" + cfg.Server.Port)
 }
 
-func newApp(db *gorm.DB, client *redis.Client, cloudinary *cloudinary.Cloudinary) *gin.Engine {
+// newStorageProvider memilih driver storage berdasarkan STORAGE_DRIVER
+func newStorageProvider(cfg *config.Config) (usecase.StorageProvider, error) {
+	switch cfg.Storage.Driver {
+	case config.STORAGE_LOCAL:
+		return usecaseImpl.NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.LocalURL), nil
+	case config.STORAGE_S3:
+		client, err := config.ConnectS3(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return usecaseImpl.NewS3Storage(client, cfg.Storage.S3Bucket, cfg.Storage.S3PublicURL), nil
+	case config.STORAGE_CLOUDINARY:
+		cloudinary, err := config.ConnectCloudinary(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return usecaseImpl.NewCloudinaryStorage(*cloudinary), nil
+	}
+
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+}
+
+func newApp(cfg *config.Config, db *gorm.DB, client *redis.Client, storageProvider usecase.StorageProvider) *gin.Engine {
 	validate := validator.New()
 
 	// Repository
 	redisRepository := repositoryImpl.NewRedisRepositoryImpl(client)
 	commentRepository := repositoryImpl.NewCommentRepository(db)
+	commentLikeRepository := repositoryImpl.NewCommentLikeRepositoryImpl(db)
 	photoRepository := repositoryImpl.NewPhotoRepository(db)
 	userRepository := repository.NewUserRepository(db)
 	userLikesPhotoRepository := repositoryImpl.NewUserLikesPhotoRepository(db)
@@ -48,27 +80,40 @@ func newApp(db *gorm.DB, client *redis.Client, cloudinary *cloudinary.Cloudinary
 	authRepository := repositoryImpl.NewAuthenticationRepositoryImpl(db)
 	tagRepository := repositoryImpl.NewTagRepositoryImpl(db)
 	photoTagRepository := repositoryImpl.NewPhotoTagsRepositoryImpl(db)
+	photoMediaRepository := repositoryImpl.NewPhotoMediaRepositoryImpl(db)
+	mediaRepository := repositoryImpl.NewMediaRepositoryImpl(db)
+	mentionRepository := repositoryImpl.NewMentionRepositoryImpl(db)
 
 	// Upload
-	cloudinaryUsecase := usecaseImpl.NewCloudinaryImpl(*cloudinary)
-	uploadFileUsecase := usecaseImpl.NewUploadFileImpl(cloudinaryUsecase)
+	uploadFileUsecase := usecaseImpl.NewUploadFileImpl(storageProvider, redisRepository, mediaRepository, photoMediaRepository, cfg.Storage.UploadTempDir)
 	uploadFileHandler := handler.NewUploadFileHandler(uploadFileUsecase)
 
+	// Timeline Set
+	timelineUsecase := usecaseImpl.NewTimelineUsecaseImpl(redisRepository, followRepository, photoRepository, cfg.Timeline)
+
+	// Mention Set
+	mentionUsecase := usecaseImpl.NewMentionUsecaseImpl(userRepository, redisRepository)
+
 	// Photo Set
 	photoUsecase := usecaseImpl.NewPhotoUsecase(
 		photoRepository,
 		commentRepository,
 		tagRepository,
 		photoTagRepository,
+		photoMediaRepository,
+		mediaRepository,
 		userLikesPhotoRepository,
 		userRepository,
-		cloudinaryUsecase,
+		mentionRepository,
+		storageProvider,
+		timelineUsecase,
+		mentionUsecase,
 	)
 
 	photoHandler := handler.NewPhotoHandler(photoUsecase, validate)
 
 	// Comment set
-	commentUsecase := usecaseImpl.NewCommentUsecase(commentRepository, photoRepository)
+	commentUsecase := usecaseImpl.NewCommentUsecase(commentRepository, commentLikeRepository, photoRepository, mentionRepository, mentionUsecase)
 	commentHandler := handler.NewCommentHandler(commentUsecase, validate)
 
 	// Like Photo set
@@ -80,13 +125,32 @@ func newApp(db *gorm.DB, client *redis.Client, cloudinary *cloudinary.Cloudinary
 	userHandler := handler.NewUserHandlerImpl(userUsecase)
 
 	// Auth Set
-	authUsecase := usecaseImpl.NewAuthenticationUsecaseImpl(authRepository, userRepository, redisRepository)
-	authHandler := handler.NewAuthHandler(authUsecase, validate)
+	tokenDenylistUsecase := usecaseImpl.NewTokenDenylistUsecaseImpl(redisRepository, helpers.AccessTokenExpiry())
+	authUsecase := usecaseImpl.NewAuthenticationUsecaseImpl(authRepository, userRepository, redisRepository, tokenDenylistUsecase)
+	authHandler := handler.NewAuthHandler(authUsecase, tokenDenylistUsecase, validate)
+	sessionHandler := handler.NewSessionHandlerImpl(authUsecase)
+	jwksHandler := handler.NewJwksHandlerImpl()
 
 	// Follow Set
-	followUsecase := usecaseImpl.NewFollowUsecaseImpl(followRepository, userRepository)
+	followUsecase := usecaseImpl.NewFollowUsecaseImpl(followRepository, userRepository, timelineUsecase)
 	followHandler := handler.NewFollowHandlerImpl(followUsecase)
 
+	// Feed Set
+	feedUsecase := usecaseImpl.NewFeedUsecaseImpl(photoRepository, commentRepository, userLikesPhotoRepository, timelineUsecase)
+	feedHandler := handler.NewFeedHandlerImpl(feedUsecase)
+
+	// Tag Set
+	tagUsecase := usecaseImpl.NewTagUsecaseImpl(tagRepository, photoTagRepository, photoRepository, commentRepository, redisRepository, cfg.Trending)
+	tagHandler := handler.NewTagHandlerImpl(tagUsecase)
+
+	// Media reaper berjalan di background selama aplikasi hidup
+	mediaReaperUsecase := usecaseImpl.NewMediaReaperUsecaseImpl(mediaRepository, redisRepository, storageProvider, cfg.Storage.UploadTempDir, cfg.Reaper)
+	go mediaReaperUsecase.Run(context.Background())
+
+	// Admin Set
+	adminUsecase := usecaseImpl.NewAdminUsecaseImpl(userRepository, photoRepository, followRepository, tokenDenylistUsecase, timelineUsecase)
+	adminHandler := handler.NewAdminHandlerImpl(adminUsecase, validate)
+
 	routerHandler := routes.RouterHandler{
 		UserHandler:       userHandler,
 		PhotoHandler:      photoHandler,
@@ -94,10 +158,15 @@ func newApp(db *gorm.DB, client *redis.Client, cloudinary *cloudinary.Cloudinary
 		LikesHandler:      userLikesPhotosHandler,
 		AuthHandler:       authHandler,
 		FollowsHandler:    followHandler,
+		FeedHandler:       feedHandler,
+		TagHandler:        tagHandler,
+		AdminHandler:      adminHandler,
+		SessionHandler:    sessionHandler,
+		JwksHandler:       jwksHandler,
 		UploadFileHandler: *uploadFileHandler,
 	}
 
-	router := routes.NewRouter(routerHandler)
+	router := routes.NewRouter(routerHandler, tokenDenylistUsecase)
 
 	return router
 }
@@ -5,10 +5,14 @@ import (
 	"strings"
 
 	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
-func Authentication() gin.HandlerFunc {
+// Authentication memverifikasi access token dan menolak token yang sudah dicabut.
+// Kalau denylist di redis tidak bisa dicek, request ditolak supaya token yang sudah dicabut tidak kembali berlaku
+func Authentication(tokenDenylistUsecase usecase.TokenDenylistUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		headerToken := c.Request.Header.Get("Authorization")
 		if headerToken == "" {
@@ -47,11 +51,37 @@ func Authentication() gin.HandlerFunc {
 				helpers.WithError(helpers.ErrTokenNotVerified),
 			).Send(c)
 
+			c.Abort()
+			return
+		}
+
+		userData := verifyToken.(jwt.MapClaims)
+
+		revoked, err := tokenDenylistUsecase.IsRevoked(c.Request.Context(), userData)
+		if err != nil {
+			log.Printf("[Authentication, IsRevoked] with error detail %v", err.Error())
+
+			helpers.NewResponse(
+				helpers.WithMessage(helpers.ErrAuthUnavailable.Error()),
+				helpers.WithError(helpers.ErrorAuthUnavailable),
+			).Send(c)
+
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			helpers.NewResponse(
+				helpers.WithMessage(helpers.ErrTokenRevoked.Error()),
+				helpers.WithError(helpers.ErrorTokenRevoked),
+			).Send(c)
+
+			c.Abort()
 			return
 		}
 
 		// menyimpan claim dari token
-		c.Set("userData", verifyToken)
+		c.Set("userData", userData)
 		c.Next()
 	}
 }
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"log"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole harus dipasang setelah Authentication, karena membaca userData dari context
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userData, ok := c.Get("userData")
+		if !ok {
+			log.Printf("[RequireRole, Get] userData is nil")
+
+			helpers.NewResponse(
+				helpers.WithMessage(helpers.ErrUnauthorized.Error()),
+				helpers.WithError(helpers.ErrorUnauthorized),
+			).Send(c)
+
+			c.Abort()
+			return
+		}
+
+		actor := helpers.GetActor(userData.(jwt.MapClaims))
+		for _, role := range roles {
+			if actor.Role == role {
+				c.Next()
+				return
+			}
+		}
+
+		log.Printf("[RequireRole] user %d with role %s is not allowed", actor.Id, actor.Role)
+
+		helpers.NewResponse(
+			helpers.WithMessage(helpers.ErrForbiddenAccess.Error()),
+			helpers.WithError(helpers.ErrorForbiddenAccess),
+		).Send(c)
+
+		c.Abort()
+	}
+}
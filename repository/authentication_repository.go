@@ -2,11 +2,21 @@ package repository
 
 import (
 	"context"
+
 	"github.com/ariwiraa/my-gram/domain"
 )
 
 type AuthenticationRepository interface {
 	Add(ctx context.Context, authentication domain.Authentication) error
 	FindByRefreshToken(ctx context.Context, token string) (*domain.Authentication, error)
+	// Rotate menandai token lama sudah dirotasi dan menyimpan token baru dalam satu transaksi.
+	// Mengembalikan ErrRefreshTokenReused kalau token lama ternyata sudah dirotasi lebih dulu.
+	// Token hasil rotasi di family yang sama yang sudah melewati umur refresh token ikut dihapus
+	Rotate(ctx context.Context, oldToken string, newAuthentication domain.Authentication) error
+	// FindActiveByUserId mengambil token yang belum dirotasi, satu untuk setiap sesi
+	FindActiveByUserId(ctx context.Context, userId uint) ([]domain.Authentication, error)
 	Delete(ctx context.Context, authentication domain.Authentication) error
+	DeleteByUserIdAndFamilyId(ctx context.Context, userId uint, familyId string) error
+	DeleteByFamilyId(ctx context.Context, familyId string) error
+	DeleteByUserId(ctx context.Context, userId uint) error
 }
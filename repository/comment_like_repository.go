@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+type CommentLikeRepository interface {
+	InsertLike(ctx context.Context, commentLike domain.CommentLike) error
+	DeleteLike(ctx context.Context, commentId uint, userId uint) error
+	VerifyUserLike(ctx context.Context, commentId uint, userId uint) (bool, error)
+	FindLikedCommentIds(ctx context.Context, userId uint, commentIds []uint) (map[uint]bool, error)
+}
@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type CommentRepository interface {
 	Create(ctx context.Context, comment domain.Comment) (*domain.Comment, error)
 	FindById(ctx context.Context, id uint) (*domain.Comment, error)
-	FindAllCommentsByPhotoId(ctx context.Context, photoId string) ([]domain.Comment, error)
+	FindAllCommentsByPhotoId(ctx context.Context, photoId string, sort string, page helpers.PageRequest) ([]domain.Comment, error)
+	FindRepliesByParentId(ctx context.Context, parentId uint, page helpers.PageRequest) ([]domain.Comment, error)
 	Update(ctx context.Context, comment domain.Comment, id uint) (*domain.Comment, error)
-	Delete(ctx context.Context, id uint)
+	Delete(ctx context.Context, id uint) error
 	CountCommentsByPhotoId(ctx context.Context, photoId string) (int64, error)
+	CountCommentsByPhotoIds(ctx context.Context, photoIds []string) (map[string]int64, error)
+	CountRepliesByParentIds(ctx context.Context, parentIds []uint) (map[uint]int64, error)
 }
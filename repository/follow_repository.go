@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type FollowRepository interface {
@@ -11,6 +12,8 @@ type FollowRepository interface {
 	VerifyUserFollow(ctx context.Context, follow domain.Follow) (bool, error)
 	CountFollowerByUserId(ctx context.Context, userId uint) (int64, error)
 	CountFollowingByUserId(ctx context.Context, userId uint) (int64, error)
-	FindFollowersByUserId(ctx context.Context, userId uint) ([]domain.User, error)
-	FindFollowingByUserId(ctx context.Context, userId uint) ([]domain.User, error)
+	FindFollowersByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Follow, error)
+	FindFollowingByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Follow, error)
+	FindFollowerIdsByUserId(ctx context.Context, userId uint) ([]uint, error)
+	FindFollowingIdsWithMinFollowers(ctx context.Context, userId uint, minFollowers int64) ([]uint, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/helpers"
@@ -15,7 +16,7 @@ type authenticationRepositoryImpl struct {
 	db *gorm.DB
 }
 
-// ExistsByRefreshToken implements repository.AuthenticationRepository.
+// FindByRefreshToken implements repository.AuthenticationRepository.
 func (r *authenticationRepositoryImpl) FindByRefreshToken(ctx context.Context, token string) (*domain.Authentication, error) {
 	authentication := new(domain.Authentication)
 	err := r.db.WithContext(ctx).First(&authentication, "refresh_token = ?", token).Error
@@ -55,6 +56,96 @@ func (r *authenticationRepositoryImpl) Delete(ctx context.Context, authenticatio
 	return nil
 }
 
+// Rotate implements repository.AuthenticationRepository.
+func (r *authenticationRepositoryImpl) Rotate(ctx context.Context, oldToken string, newAuthentication domain.Authentication) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// rotated_at IS NULL mencegah dua request refresh yang bersamaan sama-sama berhasil
+		result := tx.Model(&domain.Authentication{}).
+			Where("refresh_token = ? AND rotated_at IS NULL", oldToken).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return helpers.ErrRefreshTokenReused
+		}
+
+		// token yang dirotasi lebih lama dari umur refresh token pasti sudah kedaluwarsa,
+		// jadi tidak diperlukan lagi untuk mendeteksi pemakaian ulang
+		err := tx.Where("family_id = ? AND rotated_at < ?", newAuthentication.FamilyId, time.Now().Add(-helpers.RefreshTokenExpiry())).
+			Delete(&domain.Authentication{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&newAuthentication).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, helpers.ErrRefreshTokenReused) {
+			return err
+		}
+		log.Printf("[Rotate] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// FindActiveByUserId implements repository.AuthenticationRepository.
+func (r *authenticationRepositoryImpl) FindActiveByUserId(ctx context.Context, userId uint) ([]domain.Authentication, error) {
+	var authentications []domain.Authentication
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND rotated_at IS NULL", userId).
+		Order("last_used_at DESC").
+		Find(&authentications).Error
+	if err != nil {
+		log.Printf("[FindActiveByUserId] with error detail %v", err.Error())
+		return authentications, helpers.ErrRepository
+	}
+
+	return authentications, nil
+}
+
+// DeleteByUserIdAndFamilyId implements repository.AuthenticationRepository.
+func (r *authenticationRepositoryImpl) DeleteByUserIdAndFamilyId(ctx context.Context, userId uint, familyId string) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND family_id = ?", userId, familyId).Delete(&domain.Authentication{})
+	if result.Error != nil {
+		log.Printf("[DeleteByUserIdAndFamilyId] with error detail %v", result.Error.Error())
+		return helpers.ErrRepository
+	}
+
+	if result.RowsAffected == 0 {
+		return helpers.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteByFamilyId implements repository.AuthenticationRepository.
+func (r *authenticationRepositoryImpl) DeleteByFamilyId(ctx context.Context, familyId string) error {
+	err := r.db.WithContext(ctx).Where("family_id = ?", familyId).Delete(&domain.Authentication{}).Error
+	if err != nil {
+		log.Printf("[DeleteByFamilyId] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// DeleteByUserId implements repository.AuthenticationRepository.
+func (r *authenticationRepositoryImpl) DeleteByUserId(ctx context.Context, userId uint) error {
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&domain.Authentication{}).Error
+	if err != nil {
+		log.Printf("[DeleteByUserId] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
 func NewAuthenticationRepositoryImpl(db *gorm.DB) repository.AuthenticationRepository {
 	return &authenticationRepositoryImpl{
 		db: db,
@@ -0,0 +1,105 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"gorm.io/gorm"
+)
+
+type commentLikeRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentLikeRepositoryImpl(db *gorm.DB) repository.CommentLikeRepository {
+	return &commentLikeRepository{db: db}
+}
+
+// InsertLike implements CommentLikeRepository
+func (r *commentLikeRepository) InsertLike(ctx context.Context, commentLike domain.CommentLike) error {
+	// total_likes di comment diubah di transaksi yang sama supaya tidak selisih dengan jumlah baris like
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&commentLike).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Comment{}).
+			Where("id = ?", commentLike.CommentId).
+			UpdateColumn("total_likes", gorm.Expr("total_likes + 1")).
+			Error
+	})
+	if err != nil {
+		log.Printf("[InsertLike] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// DeleteLike implements CommentLikeRepository
+func (r *commentLikeRepository) DeleteLike(ctx context.Context, commentId uint, userId uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ?", commentId, userId).Delete(&domain.CommentLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// like sudah dihapus request lain, total_likes tidak perlu dikurangi lagi
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Model(&domain.Comment{}).
+			Where("id = ? AND total_likes > 0", commentId).
+			UpdateColumn("total_likes", gorm.Expr("total_likes - 1")).
+			Error
+	})
+	if err != nil {
+		log.Printf("[DeleteLike] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// VerifyUserLike implements CommentLikeRepository
+func (r *commentLikeRepository) VerifyUserLike(ctx context.Context, commentId uint, userId uint) (bool, error) {
+	var commentLike domain.CommentLike
+
+	err := r.db.WithContext(ctx).Where("comment_id = ? AND user_id = ?", commentId, userId).First(&commentLike).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		log.Printf("[VerifyUserLike] with error detail %v", err.Error())
+		return false, helpers.ErrRepository
+	}
+
+	return true, nil
+}
+
+// FindLikedCommentIds implements CommentLikeRepository
+func (r *commentLikeRepository) FindLikedCommentIds(ctx context.Context, userId uint, commentIds []uint) (map[uint]bool, error) {
+	var likedIds []uint
+	err := r.db.WithContext(ctx).
+		Model(&domain.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userId, commentIds).
+		Pluck("comment_id", &likedIds).
+		Error
+	if err != nil {
+		log.Printf("[FindLikedCommentIds] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	likedComments := make(map[uint]bool, len(likedIds))
+	for _, id := range likedIds {
+		likedComments[id] = true
+	}
+
+	return likedComments, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type commentRepository struct {
@@ -30,6 +31,64 @@ func (r *commentRepository) CountCommentsByPhotoId(ctx context.Context, photoId
 	return totalComment, nil
 }
 
+// photoCount dipakai untuk menampung hasil COUNT yang di group berdasarkan photo_id
+type photoCount struct {
+	PhotoId string
+	Total   int64
+}
+
+// CountCommentsByPhotoIds implements CommentRepository
+func (r *commentRepository) CountCommentsByPhotoIds(ctx context.Context, photoIds []string) (map[string]int64, error) {
+	var counts []photoCount
+	err := r.db.WithContext(ctx).
+		Model(&domain.Comment{}).
+		Select("photo_id, COUNT(*) AS total").
+		Where("photo_id IN ?", photoIds).
+		Group("photo_id").
+		Scan(&counts).
+		Error
+	if err != nil {
+		log.Printf("[CountCommentsByPhotoIds] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	totalComments := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		totalComments[count.PhotoId] = count.Total
+	}
+
+	return totalComments, nil
+}
+
+// parentCount dipakai untuk menampung hasil COUNT yang di group berdasarkan parent_id
+type parentCount struct {
+	ParentId uint
+	Total    int64
+}
+
+// CountRepliesByParentIds implements CommentRepository
+func (r *commentRepository) CountRepliesByParentIds(ctx context.Context, parentIds []uint) (map[uint]int64, error) {
+	var counts []parentCount
+	err := r.db.WithContext(ctx).
+		Model(&domain.Comment{}).
+		Select("parent_id, COUNT(*) AS total").
+		Where("parent_id IN ?", parentIds).
+		Group("parent_id").
+		Scan(&counts).
+		Error
+	if err != nil {
+		log.Printf("[CountRepliesByParentIds] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	totalReplies := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		totalReplies[count.ParentId] = count.Total
+	}
+
+	return totalReplies, nil
+}
+
 func NewCommentRepository(db *gorm.DB) repository.CommentRepository {
 	return &commentRepository{db: db}
 }
@@ -46,24 +105,39 @@ func (r *commentRepository) Create(ctx context.Context, comment domain.Comment)
 }
 
 // Delete implements CommentRepository
-func (r *commentRepository) Delete(ctx context.Context, id uint) {
-	var comment domain.Comment
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	// balasan ikut dihapus bersama parent-nya, foreign key juga cascade
+	// tapi dihapus eksplisit supaya tetap konsisten di database lama
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("parent_id = ?", id).Delete(&domain.Comment{}).Error
+		if err != nil {
+			return err
+		}
 
-	err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&comment).Error
+		return tx.Where("id = ?", id).Delete(&domain.Comment{}).Error
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return
-		}
 		log.Printf("[Delete] with error detail %v", err.Error())
-		return
+		return helpers.ErrRepository
 	}
+
+	return nil
 }
 
 // FindAll implements CommentRepository
-func (r *commentRepository) FindAllCommentsByPhotoId(ctx context.Context, photoId string) ([]domain.Comment, error) {
+func (r *commentRepository) FindAllCommentsByPhotoId(ctx context.Context, photoId string, sort string, page helpers.PageRequest) ([]domain.Comment, error) {
 	var comments []domain.Comment
 
-	err := r.db.WithContext(ctx).Find(&comments, "photo_id = ?", photoId).Error
+	scope := paginate(page, "created_at", "id", true)
+	if sort == domain.CommentSortTop {
+		scope = paginateByScore(page, "total_likes", "created_at", "id", true)
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Mentions", orderMentionsByOffset).
+		Scopes(scope).
+		Find(&comments, "photo_id = ? AND parent_id IS NULL", photoId).
+		Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return comments, helpers.ErrPhotoNotFound
@@ -74,10 +148,26 @@ func (r *commentRepository) FindAllCommentsByPhotoId(ctx context.Context, photoI
 	return comments, nil
 }
 
+// FindRepliesByParentId implements CommentRepository
+func (r *commentRepository) FindRepliesByParentId(ctx context.Context, parentId uint, page helpers.PageRequest) ([]domain.Comment, error) {
+	var replies []domain.Comment
+
+	err := r.db.WithContext(ctx).
+		Preload("Mentions", orderMentionsByOffset).
+		Scopes(paginate(page, "created_at", "id", true)).
+		Find(&replies, "parent_id = ?", parentId).
+		Error
+	if err != nil {
+		log.Printf("[FindRepliesByParentId] with error detail %v", err.Error())
+		return replies, helpers.ErrRepository
+	}
+	return replies, nil
+}
+
 // FindById implements CommentRepository
 func (r *commentRepository) FindById(ctx context.Context, id uint) (*domain.Comment, error) {
 	var comment domain.Comment
-	err := r.db.WithContext(ctx).First(&comment, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("Mentions", orderMentionsByOffset).First(&comment, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &comment, helpers.ErrCommentNotFound
@@ -92,7 +182,8 @@ func (r *commentRepository) FindById(ctx context.Context, id uint) (*domain.Comm
 // Update implements CommentRepository
 func (r *commentRepository) Update(ctx context.Context, comment domain.Comment, id uint) (*domain.Comment, error) {
 
-	err := r.db.WithContext(ctx).Model(&comment).Where("id = ?", id).Updates(&comment).Error
+	// hanya message yang boleh diubah, total_likes diubah lewat like dan mention lewat MentionRepository
+	err := r.db.WithContext(ctx).Model(&comment).Where("id = ?", id).Select("message").Omit(clause.Associations).Updates(&comment).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &comment, helpers.ErrCommentNotFound
@@ -15,13 +15,13 @@ type followRepositoryImpl struct {
 	db *gorm.DB
 }
 
-func (r *followRepositoryImpl) FindFollowingByUserId(ctx context.Context, userId uint) ([]domain.User, error) {
-	var followings []domain.User
+func (r *followRepositoryImpl) FindFollowingByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Follow, error) {
+	var followings []domain.Follow
 
-	err := r.db.WithContext(ctx).Table("follows").
-		Select("users.*").
-		Joins("INNER JOIN users ON follows.following_id = users.id").
+	err := r.db.WithContext(ctx).
+		Preload("Following").
 		Where("follows.follower_id = ?", userId).
+		Scopes(paginate(page, "follows.date_followed", "follows.id", true)).
 		Find(&followings).
 		Error
 
@@ -36,13 +36,13 @@ func (r *followRepositoryImpl) FindFollowingByUserId(ctx context.Context, userId
 	return followings, nil
 }
 
-func (r *followRepositoryImpl) FindFollowersByUserId(ctx context.Context, userId uint) ([]domain.User, error) {
-	var followers []domain.User
+func (r *followRepositoryImpl) FindFollowersByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Follow, error) {
+	var followers []domain.Follow
 
-	err := r.db.WithContext(ctx).Table("follows").
-		Select("users.*").
-		Joins("INNER JOIN users ON follows.follower_id = users.id").
+	err := r.db.WithContext(ctx).
+		Preload("Follower").
 		Where("follows.following_id = ?", userId).
+		Scopes(paginate(page, "follows.date_followed", "follows.id", true)).
 		Find(&followers).
 		Error
 
@@ -57,6 +57,43 @@ func (r *followRepositoryImpl) FindFollowersByUserId(ctx context.Context, userId
 	return followers, nil
 }
 
+func (r *followRepositoryImpl) FindFollowerIdsByUserId(ctx context.Context, userId uint) ([]uint, error) {
+	var followerIds []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Follow{}).
+		Where("following_id = ?", userId).
+		Pluck("follower_id", &followerIds).
+		Error
+
+	if err != nil {
+		log.Printf("[FindFollowerIdsByUserId] with error detail %v", err.Error())
+		return followerIds, helpers.ErrRepository
+	}
+
+	return followerIds, nil
+}
+
+// FindFollowingIdsWithMinFollowers mengambil user yang di follow oleh userId
+// dan memiliki follower lebih dari minFollowers
+func (r *followRepositoryImpl) FindFollowingIdsWithMinFollowers(ctx context.Context, userId uint, minFollowers int64) ([]uint, error) {
+	var followingIds []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Follow{}).
+		Where("follows.follower_id = ?", userId).
+		Where("(SELECT COUNT(*) FROM follows AS counted WHERE counted.following_id = follows.following_id) > ?", minFollowers).
+		Pluck("follows.following_id", &followingIds).
+		Error
+
+	if err != nil {
+		log.Printf("[FindFollowingIdsWithMinFollowers] with error detail %v", err.Error())
+		return followingIds, helpers.ErrRepository
+	}
+
+	return followingIds, nil
+}
+
 func (r *followRepositoryImpl) CountFollowerByUserId(ctx context.Context, userId uint) (int64, error) {
 	var totalFollower int64
 	err := r.db.WithContext(ctx).Model(&domain.Follow{}).Where("following_id = ?", userId).Count(&totalFollower).Error
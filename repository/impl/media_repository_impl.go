@@ -0,0 +1,204 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"gorm.io/gorm"
+)
+
+type mediaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// Create implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) Create(ctx context.Context, media domain.Media) error {
+	err := r.db.WithContext(ctx).Create(&media).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return helpers.ErrMediaAlreadyExists
+		}
+		log.Printf("[Create] with error details %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// FindByIds implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) FindByIds(ctx context.Context, ids []string) ([]domain.Media, error) {
+	var media []domain.Media
+	err := r.db.WithContext(ctx).Find(&media, "id IN ?", ids).Error
+	if err != nil {
+		log.Printf("[FindByIds] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return media, nil
+}
+
+// Claim implements repository.MediaRepository.
+// Kondisi claimed = false ada di query update supaya dua request yang bersamaan tidak bisa
+// meng-claim media yang sama
+func (r *mediaRepositoryImpl) Claim(ctx context.Context, userId uint, ids []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Media{}).
+			Where("id IN ? AND user_id = ? AND claimed = ?", ids, userId, false).
+			Update("claimed", true)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected != int64(len(ids)) {
+			return helpers.ErrMediaAlreadyClaimed
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, helpers.ErrMediaAlreadyClaimed) {
+			return err
+		}
+		log.Printf("[Claim] with error details %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// Release implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) Release(ctx context.Context, ids []string) error {
+	err := r.db.WithContext(ctx).Model(&domain.Media{}).Where("id IN ?", ids).Update("claimed", false).Error
+	if err != nil {
+		log.Printf("[Release] with error details %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// FindUnclaimedBefore implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) FindUnclaimedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Media, error) {
+	var media []domain.Media
+	err := r.db.WithContext(ctx).
+		Where("claimed = ? AND created_at < ?", false, before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+	if err != nil {
+		log.Printf("[FindUnclaimedBefore] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return media, nil
+}
+
+// DeleteUnclaimed implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) DeleteUnclaimed(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND claimed = ?", id, false).Delete(&domain.Media{})
+	if result.Error != nil {
+		log.Printf("[DeleteUnclaimed] with error details %v", result.Error.Error())
+		return helpers.ErrRepository
+	}
+
+	if result.RowsAffected == 0 {
+		return helpers.ErrMediaAlreadyClaimed
+	}
+
+	return nil
+}
+
+// mediaUrlColumns adalah kolom url file yang ada di media, foto dan slide.
+// Foto lama dibuat sebelum ada tabel media, jadi url di foto dan slide ikut diperiksa.
+// Semua kolom ini diberi index supaya pencocokan per halaman storage tidak membaca seluruh tabel
+var mediaUrlColumns = []string{"photo_url", "poster_url", "medium_url", "thumbnail_url"}
+
+var mediaUrlModels = []interface{}{&domain.Media{}, &domain.Photo{}, &domain.PhotoMedia{}}
+
+// FindReferencedUrls implements repository.MediaRepository.
+func (r *mediaRepositoryImpl) FindReferencedUrls(ctx context.Context, urls []string) ([]string, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		wanted[url] = true
+	}
+
+	var referenced []string
+	for _, model := range mediaUrlModels {
+		query := r.db.WithContext(ctx).Model(model).Select(mediaUrlColumns)
+		for _, column := range mediaUrlColumns {
+			query = query.Or(column+" IN ?", urls)
+		}
+
+		var rows []struct {
+			PhotoUrl     string
+			PosterUrl    string
+			MediumUrl    string
+			ThumbnailUrl string
+		}
+		err := query.Find(&rows).Error
+		if err != nil {
+			log.Printf("[FindReferencedUrls] with error details %v", err.Error())
+			return nil, helpers.ErrRepository
+		}
+
+		// satu baris bisa cocok lewat kolom lain, jadi hanya url yang dicari yang dikembalikan
+		for _, row := range rows {
+			for _, url := range []string{row.PhotoUrl, row.PosterUrl, row.MediumUrl, row.ThumbnailUrl} {
+				if wanted[url] {
+					referenced = append(referenced, url)
+				}
+			}
+		}
+	}
+
+	return referenced, nil
+}
+
+// IsKeyReferenced implements repository.MediaRepository.
+// Query LIKE tidak memakai index, jadi hanya dipakai untuk file yang tidak ditemukan lewat FindReferencedUrls
+func (r *mediaRepositoryImpl) IsKeyReferenced(ctx context.Context, key string) (bool, error) {
+	pattern := "%/" + escapeLike(key)
+
+	for _, model := range mediaUrlModels {
+		query := r.db.WithContext(ctx).Model(model)
+		if _, ok := model.(*domain.Media); ok {
+			query = query.Or("public_id = ?", key)
+		}
+		for _, column := range mediaUrlColumns {
+			query = query.Or(column+" LIKE ? OR "+column+" LIKE ?", pattern, pattern+".%")
+		}
+
+		var total int64
+		err := query.Count(&total).Error
+		if err != nil {
+			log.Printf("[IsKeyReferenced] with error details %v", err.Error())
+			return false, helpers.ErrRepository
+		}
+
+		if total > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// escapeLike supaya % dan _ di key dibaca sebagai karakter biasa oleh LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func NewMediaRepositoryImpl(db *gorm.DB) repository.MediaRepository {
+	return &mediaRepositoryImpl{db: db}
+}
@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"context"
+	"log"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"gorm.io/gorm"
+)
+
+type mentionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewMentionRepositoryImpl(db *gorm.DB) repository.MentionRepository {
+	return &mentionRepositoryImpl{db: db}
+}
+
+// ReplaceByPhotoId implements repository.MentionRepository.
+func (r *mentionRepositoryImpl) ReplaceByPhotoId(ctx context.Context, photoId string, mentions []domain.Mention) ([]domain.Mention, error) {
+	for i := range mentions {
+		mentions[i].PhotoId = &photoId
+		mentions[i].CommentId = nil
+	}
+
+	err := r.replace(ctx, "photo_id", photoId, mentions)
+	if err != nil {
+		log.Printf("[ReplaceByPhotoId] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return mentions, nil
+}
+
+// ReplaceByCommentId implements repository.MentionRepository.
+func (r *mentionRepositoryImpl) ReplaceByCommentId(ctx context.Context, commentId uint, mentions []domain.Mention) ([]domain.Mention, error) {
+	for i := range mentions {
+		mentions[i].CommentId = &commentId
+		mentions[i].PhotoId = nil
+	}
+
+	err := r.replace(ctx, "comment_id", commentId, mentions)
+	if err != nil {
+		log.Printf("[ReplaceByCommentId] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return mentions, nil
+}
+
+// replace menghapus mention lama milik sumber yang sama lalu menyimpan mention baru dalam satu transaksi
+func (r *mentionRepositoryImpl) replace(ctx context.Context, sourceColumn string, sourceId interface{}, mentions []domain.Mention) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(sourceColumn+" = ?", sourceId).Delete(&domain.Mention{}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(mentions) == 0 {
+			return nil
+		}
+
+		return tx.Create(&mentions).Error
+	})
+}
+
+// orderMentionsByOffset dipakai saat preload supaya mention urut sesuai posisinya di text
+func orderMentionsByOffset(db *gorm.DB) *gorm.DB {
+	return db.Order("text_offset ASC")
+}
@@ -0,0 +1,49 @@
+package impl
+
+import (
+	"fmt"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"gorm.io/gorm"
+)
+
+// paginate mengurutkan berdasarkan (created_at, id) secara descending dan
+// mengambil limit+1 baris supaya usecase tahu masih ada halaman berikutnya atau tidak
+func paginate(page helpers.PageRequest, createdAtColumn, idColumn string, numericId bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if page.Cursor != nil {
+			var cursorId interface{} = page.Cursor.Id
+			if numericId {
+				cursorId = page.Cursor.UintId()
+			}
+
+			db = db.Where(fmt.Sprintf("(%s, %s) < (?, ?)", createdAtColumn, idColumn), page.Cursor.CreatedAt, cursorId)
+		}
+
+		return db.
+			Order(createdAtColumn + " DESC").
+			Order(idColumn + " DESC").
+			Limit(page.Limit + 1)
+	}
+}
+
+// paginateByScore sama seperti paginate tapi diurutkan dulu berdasarkan scoreColumn secara descending,
+// cursor-nya harus membawa score
+func paginateByScore(page helpers.PageRequest, scoreColumn, createdAtColumn, idColumn string, numericId bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if page.Cursor != nil && page.Cursor.Score != nil {
+			var cursorId interface{} = page.Cursor.Id
+			if numericId {
+				cursorId = page.Cursor.UintId()
+			}
+
+			db = db.Where(fmt.Sprintf("(%s, %s, %s) < (?, ?, ?)", scoreColumn, createdAtColumn, idColumn), *page.Cursor.Score, page.Cursor.CreatedAt, cursorId)
+		}
+
+		return db.
+			Order(scoreColumn + " DESC").
+			Order(createdAtColumn + " DESC").
+			Order(idColumn + " DESC").
+			Limit(page.Limit + 1)
+	}
+}
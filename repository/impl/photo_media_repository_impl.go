@@ -0,0 +1,75 @@
+package impl
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"gorm.io/gorm"
+)
+
+type photoMediaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// FindByPhotoId implements repository.PhotoMediaRepository.
+func (r *photoMediaRepositoryImpl) FindByPhotoId(ctx context.Context, photoId string) ([]domain.PhotoMedia, error) {
+	var media []domain.PhotoMedia
+	err := r.db.WithContext(ctx).Scopes(orderMediaByPosition).Find(&media, "photo_id = ?", photoId).Error
+	if err != nil {
+		log.Printf("[FindByPhotoId] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return media, nil
+}
+
+// FindSimilar implements repository.PhotoMediaRepository.
+func (r *photoMediaRepositoryImpl) FindSimilar(ctx context.Context, hash int64, maxDistance int, excludePhotoId string, limit int) ([]domain.SimilarPhoto, error) {
+	var similar []domain.SimilarPhoto
+	err := r.db.WithContext(ctx).Model(&domain.PhotoMedia{}).
+		Select("photo_id, MIN("+hammingDistanceSQL+") AS distance", hash).
+		Where("perceptual_hash <> 0 AND photo_id <> ?", excludePhotoId).
+		Where(hammingDistanceSQL+" <= ?", hash, maxDistance).
+		Group("photo_id").
+		Order("distance ASC").
+		Limit(limit).
+		Scan(&similar).Error
+	if err != nil {
+		log.Printf("[FindSimilar] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return similar, nil
+}
+
+// HasSimilarByUser implements repository.PhotoMediaRepository.
+func (r *photoMediaRepositoryImpl) HasSimilarByUser(ctx context.Context, userId uint, hash int64, maxDistance int, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.PhotoMedia{}).
+		Joins("JOIN photos ON photos.id = photo_media.photo_id").
+		Where("photos.user_id = ? AND photos.created_at >= ? AND photo_media.perceptual_hash <> 0", userId, since).
+		Where(hammingDistanceSQL+" <= ?", hash, maxDistance).
+		Count(&count).Error
+	if err != nil {
+		log.Printf("[HasSimilarByUser] with error details %v", err.Error())
+		return false, helpers.ErrRepository
+	}
+
+	return count > 0, nil
+}
+
+// hammingDistanceSQL menghitung jumlah bit yang berbeda antara perceptual_hash dan parameter hash
+const hammingDistanceSQL = "length(replace(((photo_media.perceptual_hash # ?::bigint)::bit(64))::text, '0', ''))"
+
+// orderMediaByPosition dipakai juga saat preload supaya slide selalu berurutan
+func orderMediaByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func NewPhotoMediaRepositoryImpl(db *gorm.DB) repository.PhotoMediaRepository {
+	return &photoMediaRepositoryImpl{db: db}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"slices"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/helpers"
@@ -21,7 +22,7 @@ func NewPhotoRepository(db *gorm.DB) repository.PhotoRepository {
 
 func (r *photoRepository) FindPhotosByIDList(ctx context.Context, photoIds []string) ([]domain.Photo, error) {
 	var photos []domain.Photo
-	err := r.db.WithContext(ctx).Preload("User").Preload("Comments").Find(&photos, "id IN ?", photoIds).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Comments").Preload("Tags").Preload("Media", orderMediaByPosition).Preload("Mentions", orderMentionsByOffset).Find(&photos, "id IN ?", photoIds).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return photos, helpers.ErrPhotoNotFound
@@ -32,9 +33,15 @@ func (r *photoRepository) FindPhotosByIDList(ctx context.Context, photoIds []str
 	return photos, err
 }
 
-func (r *photoRepository) FindByUserId(ctx context.Context, id uint) ([]domain.Photo, error) {
+func (r *photoRepository) FindByUserId(ctx context.Context, id uint, page helpers.PageRequest) ([]domain.Photo, error) {
 	var photos []domain.Photo
-	err := r.db.WithContext(ctx).Preload("Comments").Find(&photos, "user_id = ?", id).Error
+	err := r.db.WithContext(ctx).
+		Preload("Comments").
+		Preload("Media", orderMediaByPosition).
+		Preload("Mentions", orderMentionsByOffset).
+		Scopes(paginate(page, "created_at", "id", false)).
+		Find(&photos, "user_id = ?", id).
+		Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return photos, helpers.ErrUserNotFound
@@ -46,6 +53,68 @@ func (r *photoRepository) FindByUserId(ctx context.Context, id uint) ([]domain.P
 	return photos, nil
 }
 
+// FindByTagId mengambil foto yang memakai tag, terbaru lebih dulu
+func (r *photoRepository) FindByTagId(ctx context.Context, tagId uint, page helpers.PageRequest) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	taggedPhotos := r.db.Model(&domain.PhotoTags{}).Select("photo_id").Where("tag_id = ?", tagId)
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Tags").
+		Preload("Media", orderMediaByPosition).
+		Preload("Mentions", orderMentionsByOffset).
+		Scopes(paginate(page, "created_at", "id", false)).
+		Find(&photos, "id IN (?)", taggedPhotos).
+		Error
+	if err != nil {
+		log.Printf("[FindByTagId] with error detail %v", err.Error())
+		return photos, helpers.ErrRepository
+	}
+
+	return photos, nil
+}
+
+// FindByFollowerId mengambil foto dari semua user yang di follow oleh followerId
+func (r *photoRepository) FindByFollowerId(ctx context.Context, followerId uint, page helpers.PageRequest) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.db.WithContext(ctx).
+		Select("photos.*").
+		Preload("User").
+		Preload("Tags").
+		Preload("Media", orderMediaByPosition).
+		Preload("Mentions", orderMentionsByOffset).
+		Joins("INNER JOIN follows ON follows.following_id = photos.user_id").
+		Where("follows.follower_id = ?", followerId).
+		Scopes(paginate(page, "photos.created_at", "photos.id", false)).
+		Find(&photos).
+		Error
+	if err != nil {
+		log.Printf("[FindByFollowerId] with error detail %v", err.Error())
+		return photos, helpers.ErrRepository
+	}
+
+	return photos, nil
+}
+
+func (r *photoRepository) FindByUserIds(ctx context.Context, userIds []uint, page helpers.PageRequest) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Tags").
+		Preload("Media", orderMediaByPosition).
+		Preload("Mentions", orderMentionsByOffset).
+		Where("user_id IN ?", userIds).
+		Scopes(paginate(page, "created_at", "id", false)).
+		Find(&photos).
+		Error
+	if err != nil {
+		log.Printf("[FindByUserIds] with error detail %v", err.Error())
+		return photos, helpers.ErrRepository
+	}
+
+	return photos, nil
+}
+
 func (r *photoRepository) CountPhotoByUserId(ctx context.Context, userId uint) (int64, error) {
 	var totalPosts int64
 	err := r.db.WithContext(ctx).Model(&domain.Photo{}).Where("user_id = ?", userId).Count(&totalPosts).Error
@@ -60,6 +129,57 @@ func (r *photoRepository) CountPhotoByUserId(ctx context.Context, userId uint) (
 	return totalPosts, nil
 }
 
+// FindRecentEntriesByUserId implements PhotoRepository
+func (r *photoRepository) FindRecentEntriesByUserId(ctx context.Context, userId uint, limit int) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.db.WithContext(ctx).
+		Select("id", "created_at").
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&photos).
+		Error
+	if err != nil {
+		log.Printf("[FindRecentEntriesByUserId] with error detail %v", err.Error())
+		return photos, helpers.ErrRepository
+	}
+
+	return photos, nil
+}
+
+// FindRecentEntriesByFollowerId implements PhotoRepository
+func (r *photoRepository) FindRecentEntriesByFollowerId(ctx context.Context, followerId uint, limit int) ([]domain.Photo, error) {
+	var photos []domain.Photo
+	err := r.db.WithContext(ctx).
+		Select("photos.id", "photos.created_at").
+		Joins("INNER JOIN follows ON follows.following_id = photos.user_id").
+		Where("follows.follower_id = ?", followerId).
+		Order("photos.created_at DESC").
+		Order("photos.id DESC").
+		Limit(limit).
+		Find(&photos).
+		Error
+	if err != nil {
+		log.Printf("[FindRecentEntriesByFollowerId] with error detail %v", err.Error())
+		return photos, helpers.ErrRepository
+	}
+
+	return photos, nil
+}
+
+// FindIdsByUserId implements PhotoRepository
+func (r *photoRepository) FindIdsByUserId(ctx context.Context, userId uint) ([]string, error) {
+	var photoIds []string
+	err := r.db.WithContext(ctx).Model(&domain.Photo{}).Where("user_id = ?", userId).Pluck("id", &photoIds).Error
+	if err != nil {
+		log.Printf("[FindIdsByUserId] with error detail %v", err.Error())
+		return photoIds, helpers.ErrRepository
+	}
+
+	return photoIds, nil
+}
+
 // IsPhotoExist implements PhotoRepository
 func (r *photoRepository) IsPhotoExist(ctx context.Context, id string) error {
 	var photo domain.Photo
@@ -87,24 +207,63 @@ func (r *photoRepository) Create(ctx context.Context, photo domain.Photo) (domai
 }
 
 // Delete implements PhotoRepository
+// Tag dan baris media yang sudah di-claim foto ini ikut dihapus dalam satu transaksi,
+// supaya tidak ada media yang tercatat terpakai padahal fotonya sudah tidak ada
 func (r *photoRepository) Delete(ctx context.Context, photo domain.Photo) error {
-	err := r.db.WithContext(ctx).Where("id = ?", photo.ID).Delete(&photo).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return helpers.ErrPhotoNotFound
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("photo_id = ?", photo.ID).Delete(&domain.PhotoTags{}).Error
+		if err != nil {
+			return err
 		}
-		log.Printf("[IsPhotoExist] with error detail %v", err.Error())
+
+		err = deleteClaimedMedia(tx, photo.UserId, photoMediaUrls(photo.PhotoUrl, photo.Media))
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", photo.ID).Delete(&photo).Error
+	})
+	if err != nil {
+		log.Printf("[Delete] with error detail %v", err.Error())
 		return helpers.ErrRepository
 	}
 
 	return nil
 }
 
+// deleteClaimedMedia menghapus baris media milik userId yang di-claim sebagai slide dengan url tersebut.
+// Slide menyalin url dari media, jadi url itulah penghubung keduanya
+func deleteClaimedMedia(tx *gorm.DB, userId uint, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	return tx.Where("user_id = ? AND claimed = ? AND photo_url IN ?", userId, true, urls).Delete(&domain.Media{}).Error
+}
+
+// photoMediaUrls mengumpulkan url file utama foto dan slide-nya
+func photoMediaUrls(coverUrl string, media []domain.PhotoMedia) []string {
+	urls := make([]string, 0, len(media)+1)
+	if coverUrl != "" {
+		urls = append(urls, coverUrl)
+	}
+	for _, item := range media {
+		urls = append(urls, item.PhotoUrl)
+	}
+
+	return urls
+}
+
 // FindAll implements PhotoRepository
-func (r *photoRepository) FindAll(ctx context.Context) ([]domain.Photo, error) {
+func (r *photoRepository) FindAll(ctx context.Context, page helpers.PageRequest) ([]domain.Photo, error) {
 	var photos []domain.Photo
 
-	err := r.db.WithContext(ctx).Find(&photos).Error
+	err := r.db.WithContext(ctx).
+		Preload("Media", orderMediaByPosition).
+		Preload("Mentions", orderMentionsByOffset).
+		Scopes(paginate(page, "created_at", "id", false)).
+		Find(&photos).
+		Error
 	if err != nil {
 		log.Printf("[FindAll] with error detail %v", err.Error())
 		return photos, helpers.ErrRepository
@@ -115,10 +274,10 @@ func (r *photoRepository) FindAll(ctx context.Context) ([]domain.Photo, error) {
 // FindById implements PhotoRepository
 func (r *photoRepository) FindById(ctx context.Context, id string) (domain.Photo, error) {
 	var photo domain.Photo
-	err := r.db.WithContext(ctx).Preload("User").Preload("Comments").First(&photo, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Comments").Preload("Media", orderMediaByPosition).Preload("Mentions", orderMentionsByOffset).First(&photo, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return photo, err
+			return photo, helpers.ErrPhotoNotFound
 		}
 		log.Printf("[FindById] with error detail %v", err.Error())
 		return photo, helpers.ErrRepository
@@ -128,14 +287,82 @@ func (r *photoRepository) FindById(ctx context.Context, id string) (domain.Photo
 }
 
 // Update implements PhotoRepository
-func (r *photoRepository) Update(ctx context.Context, photo domain.Photo, id string) (domain.Photo, error) {
+// Slide, tag dan kolom cover disimpan dalam satu transaksi supaya tidak ada yang tertinggal kalau salah satunya gagal.
+// Kolom ditulis dengan Select supaya nilai kosong dari cover baru tetap tersimpan
+func (r *photoRepository) Update(ctx context.Context, photo domain.Photo, id string, tagIds []uint) (domain.Photo, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// foto lama tanpa baris media tidak punya slide yang perlu diurutkan
+		if len(photo.Media) > 0 {
+			mediaIds := make([]string, 0, len(photo.Media))
+			for _, item := range photo.Media {
+				mediaIds = append(mediaIds, item.ID)
+			}
 
-	err := r.db.WithContext(ctx).Model(&photo).Where("id = ?", id).Updates(&photo).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return photo, helpers.ErrPhotoNotFound
+			// media dari slide yang dibuang ikut dihapus, filenya dibersihkan oleh usecase
+			var removedUrls []string
+			err := tx.Model(&domain.PhotoMedia{}).Where("photo_id = ? AND id NOT IN ?", id, mediaIds).Pluck("photo_url", &removedUrls).Error
+			if err != nil {
+				return err
+			}
+
+			err = deleteClaimedMedia(tx, photo.UserId, removedUrls)
+			if err != nil {
+				return err
+			}
+
+			err = tx.Where("photo_id = ? AND id NOT IN ?", id, mediaIds).Delete(&domain.PhotoMedia{}).Error
+			if err != nil {
+				return err
+			}
+
+			for position, item := range photo.Media {
+				err = tx.Model(&domain.PhotoMedia{}).
+					Where("id = ? AND photo_id = ?", item.ID, id).
+					Update("position", position).Error
+				if err != nil {
+					return err
+				}
+			}
 		}
-		log.Printf("[IsPhotoExist] with error detail %v", err.Error())
+
+		// tag yang tetap dipakai tidak dibuat ulang supaya created_at-nya tidak ikut dihitung trending lagi
+		removedTags := tx.Where("photo_id = ?", id)
+		if len(tagIds) > 0 {
+			removedTags = removedTags.Where("tag_id NOT IN ?", tagIds)
+		}
+		err := removedTags.Delete(&domain.PhotoTags{}).Error
+		if err != nil {
+			return err
+		}
+
+		var existingTagIds []uint
+		err = tx.Model(&domain.PhotoTags{}).Where("photo_id = ?", id).Pluck("tag_id", &existingTagIds).Error
+		if err != nil {
+			return err
+		}
+
+		photoTags := make([]domain.PhotoTags, 0, len(tagIds))
+		for _, tagId := range tagIds {
+			if !slices.Contains(existingTagIds, tagId) {
+				photoTags = append(photoTags, domain.PhotoTags{PhotoId: id, TagId: tagId})
+			}
+		}
+
+		if len(photoTags) > 0 {
+			err = tx.Create(&photoTags).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&photo).
+			Select("caption", "media_type", "photo_url", "poster_url", "medium_url", "thumbnail_url",
+				"width", "height", "duration", "blur_hash", "dominant_color", "updated_at").
+			Where("id = ?", id).
+			Updates(&photo).Error
+	})
+	if err != nil {
+		log.Printf("[Update] with error detail %v", err.Error())
 		return photo, helpers.ErrRepository
 	}
 
@@ -56,6 +56,18 @@ func (r *photoTagsRepositoryImpl) Delete(ctx context.Context, photoId string) er
 	return nil
 }
 
+// CountByTagId implements repository.PhotoTagsRepository.
+func (r *photoTagsRepositoryImpl) CountByTagId(ctx context.Context, tagId uint) (int64, error) {
+	var totalPhotos int64
+	err := r.db.WithContext(ctx).Model(&domain.PhotoTags{}).Where("tag_id = ?", tagId).Count(&totalPhotos).Error
+	if err != nil {
+		log.Printf("[CountByTagId] with error details %v", err.Error())
+		return 0, helpers.ErrRepository
+	}
+
+	return totalPhotos, nil
+}
+
 func NewPhotoTagsRepositoryImpl(db *gorm.DB) repository.PhotoTagsRepository {
 	return &photoTagsRepositoryImpl{db: db}
 }
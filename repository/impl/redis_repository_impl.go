@@ -8,6 +8,21 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// script dijalankan atomik di redis supaya pengecekan isi key dan perubahannya tidak bisa disela client lain
+var (
+	delIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	expireIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
 type redisRepositoryImpl struct {
 	client *redis.Client
 }
@@ -28,6 +43,11 @@ func (r *redisRepositoryImpl) Get(ctx context.Context, key string) (interface{},
 	return value, nil
 }
 
+// MGet implements repository.RedisRepository.
+func (r *redisRepositoryImpl) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return r.client.MGet(ctx, keys...).Result()
+}
+
 // Set implements repository.RedisRepository.
 func (r *redisRepositoryImpl) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
 	err := r.client.Set(ctx, key, value, ttl)
@@ -37,3 +57,135 @@ func (r *redisRepositoryImpl) Set(ctx context.Context, key string, value string,
 
 	return nil
 }
+
+// Del implements repository.RedisRepository.
+func (r *redisRepositoryImpl) Del(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// SetNX implements repository.RedisRepository.
+func (r *redisRepositoryImpl) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// DelIfValue implements repository.RedisRepository.
+func (r *redisRepositoryImpl) DelIfValue(ctx context.Context, key string, value string) (bool, error) {
+	deleted, err := delIfValueScript.Run(ctx, r.client, []string{key}, value).Int64()
+	return deleted == 1, err
+}
+
+// ExpireIfValue implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ExpireIfValue(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	updated, err := expireIfValueScript.Run(ctx, r.client, []string{key}, value, ttl.Milliseconds()).Int64()
+	return updated == 1, err
+}
+
+// Incr implements repository.RedisRepository.
+func (r *redisRepositoryImpl) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	value, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return value, err
+	}
+
+	if value == 1 {
+		err = r.client.Expire(ctx, key, ttl).Err()
+	}
+
+	return value, err
+}
+
+// ZAddCapped implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZAddCapped(ctx context.Context, keys []string, member repository.ZMember, maxLength int64) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZAdd(ctx, key, redis.Z{Score: member.Score, Member: member.Member})
+			pipe.ZRemRangeByRank(ctx, key, 0, -(maxLength + 1))
+		}
+		return nil
+	})
+
+	return err
+}
+
+// ZAddManyCapped implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZAddManyCapped(ctx context.Context, key string, members []repository.ZMember, maxLength int64) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]redis.Z, 0, len(members))
+	for _, member := range members {
+		values = append(values, redis.Z{Score: member.Score, Member: member.Member})
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, values...)
+		pipe.ZRemRangeByRank(ctx, key, 0, -(maxLength + 1))
+		return nil
+	})
+
+	return err
+}
+
+// ZRem implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZRem(ctx context.Context, keys []string, member string) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZRem(ctx, key, member)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// ZRemMany implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZRemMany(ctx context.Context, key string, members []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		values = append(values, member)
+	}
+
+	return r.client.ZRem(ctx, key, values...).Err()
+}
+
+// ZRevRangeByScore implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZRevRangeByScore(ctx context.Context, key, min, max string, count int64) ([]repository.ZMember, error) {
+	values, err := r.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   min,
+		Max:   max,
+		Count: count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]repository.ZMember, 0, len(values))
+	for _, value := range values {
+		members = append(members, repository.ZMember{
+			Member: value.Member.(string),
+			Score:  value.Score,
+		})
+	}
+
+	return members, nil
+}
+
+// ZCard implements repository.RedisRepository.
+func (r *redisRepositoryImpl) ZCard(ctx context.Context, key string) (int64, error) {
+	return r.client.ZCard(ctx, key).Result()
+}
+
+// XAdd implements repository.RedisRepository.
+func (r *redisRepositoryImpl) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLength int64) error {
+	return r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLength,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type tagRepositoryImpl struct {
@@ -30,23 +33,31 @@ func (r *tagRepositoryImpl) FindById(ctx context.Context, id uint) (*domain.Tag,
 	return &tag, nil
 }
 
+// AddTagIfNotExists implements repository.TagRepository.
+// ON CONFLICT membuat dua request yang menambah tag yang sama bersamaan tetap mendapat tag yang sama
 func (r *tagRepositoryImpl) AddTagIfNotExists(ctx context.Context, name string) (*domain.Tag, error) {
-	var existingTag domain.Tag
-	err := r.db.WithContext(ctx).Where("name = ?", name).First(&existingTag).Error
+	tag := domain.Tag{Name: name}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&tag).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// Tag dengan name tersebut belum ada, tambahkan tag baru
-			newTag := domain.Tag{Name: name}
-			newTagSaved, err := r.Add(ctx, newTag)
-			if err != nil {
-				return nil, err
-			}
-			// Kembalikan tag baru yang telah dibuat
-			return newTagSaved, nil
-		}
+		log.Printf("[AddTagIfNotExists, Create] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	if tag.ID != 0 {
+		return &tag, nil
 	}
+
 	// Tag sudah ada dalam database, kembalikan tag yang ada
-	return &existingTag, nil
+	err = r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		log.Printf("[AddTagIfNotExists, First] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return &tag, nil
 }
 
 // Add implements repository.TagRepository.
@@ -60,15 +71,77 @@ func (r *tagRepositoryImpl) Add(ctx context.Context, tag domain.Tag) (*domain.Ta
 	return &tag, nil
 }
 
-// FindByName implements repository.TagRepository.
-func (r *tagRepositoryImpl) FindByName(ctx context.Context, name string) ([]domain.Tag, error) {
-	var tags []domain.Tag
-	err := r.db.WithContext(ctx).Where("lower(name) LIKE lower(?)", "%"+name+"%").Find(&tags).Error
+// FindOneByName implements repository.TagRepository.
+func (r *tagRepositoryImpl) FindOneByName(ctx context.Context, name string) (*domain.Tag, error) {
+	var tag domain.Tag
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return tags, helpers.ErrTagNotFound
+			return &tag, helpers.ErrTagNotFound
 		}
-		log.Printf("[FindByName] with error details %v", err.Error())
+		log.Printf("[FindOneByName] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return &tag, nil
+}
+
+// SearchByPrefix implements repository.TagRepository.
+func (r *tagRepositoryImpl) SearchByPrefix(ctx context.Context, prefix string, limit int) ([]domain.TagUsage, error) {
+	var tags []domain.TagUsage
+
+	// underscore adalah wildcard di LIKE, sedangkan nama tag boleh mengandung underscore
+	pattern := strings.ReplaceAll(prefix, "_", `\_`) + "%"
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.Tag{}).
+		Select("tags.id, tags.name, COUNT(photo_tags.tag_id) AS total_photos").
+		Joins("LEFT JOIN photo_tags ON photo_tags.tag_id = tags.id").
+		Where("tags.name LIKE ?", pattern).
+		Group("tags.id, tags.name").
+		Order("total_photos DESC").
+		Order("tags.name ASC").
+		Limit(limit).
+		Scan(&tags).
+		Error
+	if err != nil {
+		log.Printf("[SearchByPrefix] with error details %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	return tags, nil
+}
+
+// FindTrending implements repository.TagRepository.
+func (r *tagRepositoryImpl) FindTrending(ctx context.Context, now time.Time, window time.Duration, minRecentPhotos int64, limit int) ([]domain.TrendingTag, error) {
+	var tags []domain.TrendingTag
+
+	recentSince := now.Add(-window)
+	previousSince := now.Add(-2 * window)
+
+	recentPhotos := "COUNT(*) FILTER (WHERE photo_tags.created_at >= @recent)"
+	previousPhotos := "COUNT(*) FILTER (WHERE photo_tags.created_at < @recent)"
+	args := map[string]interface{}{"recent": recentSince}
+
+	// +1 supaya tag yang baru muncul tidak dibagi nol dan tag dengan satu dua foto tidak langsung melonjak
+	err := r.db.WithContext(ctx).
+		Table("photo_tags").
+		Select("tags.id, tags.name, "+
+			recentPhotos+" AS recent_photos, "+
+			previousPhotos+" AS previous_photos, "+
+			"("+recentPhotos+" + 1)::float / ("+previousPhotos+" + 1) AS growth", args).
+		Joins("JOIN tags ON tags.id = photo_tags.tag_id").
+		Where("photo_tags.created_at >= ? AND photo_tags.created_at <= ?", previousSince, now).
+		Group("tags.id, tags.name").
+		Having(recentPhotos+" >= @min", map[string]interface{}{"recent": recentSince, "min": minRecentPhotos}).
+		Order("growth DESC").
+		Order("recent_photos DESC").
+		Order("tags.name ASC").
+		Limit(limit).
+		Scan(&tags).
+		Error
+	if err != nil {
+		log.Printf("[FindTrending] with error details %v", err.Error())
 		return nil, helpers.ErrRepository
 	}
 
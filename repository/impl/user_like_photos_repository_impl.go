@@ -58,6 +58,29 @@ func (r *userLikesPhotoRepository) CountUsersWhoLikedPhotoByPhotoId(ctx context.
 	return totalLikes, nil
 }
 
+// CountLikesByPhotoIds implements UserLikesPhotoRepository
+func (r *userLikesPhotoRepository) CountLikesByPhotoIds(ctx context.Context, photoIds []string) (map[string]int64, error) {
+	var counts []photoCount
+	err := r.db.WithContext(ctx).
+		Model(&domain.UserLikesPhoto{}).
+		Select("photo_id, COUNT(*) AS total").
+		Where("photo_id IN ?", photoIds).
+		Group("photo_id").
+		Scan(&counts).
+		Error
+	if err != nil {
+		log.Printf("[CountLikesByPhotoIds] with error detail %v", err.Error())
+		return nil, helpers.ErrRepository
+	}
+
+	totalLikes := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		totalLikes[count.PhotoId] = count.Total
+	}
+
+	return totalLikes, nil
+}
+
 // DeleteLike implements UserLikesPhotoRepository
 func (r *userLikesPhotoRepository) DeleteLike(ctx context.Context, photoId string, userId uint) {
 	var userLikesPhoto domain.UserLikesPhoto
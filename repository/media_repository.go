@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+type MediaRepository interface {
+	Create(ctx context.Context, media domain.Media) error
+	FindByIds(ctx context.Context, ids []string) ([]domain.Media, error)
+	// Claim menandai media milik userId sebagai terpakai, gagal semuanya kalau ada satu saja
+	// yang bukan milik userId atau sudah di-claim
+	Claim(ctx context.Context, userId uint, ids []string) error
+	// Release membatalkan Claim ketika postingan gagal disimpan
+	Release(ctx context.Context, ids []string) error
+	FindUnclaimedBefore(ctx context.Context, before time.Time, limit int) ([]domain.Media, error)
+	// DeleteUnclaimed hanya menghapus media yang belum di-claim, media yang keburu di-claim
+	// mengembalikan ErrMediaAlreadyClaimed
+	DeleteUnclaimed(ctx context.Context, id string) error
+	// FindReferencedUrls mengembalikan url dari urls yang masih dipakai oleh media, foto atau slide
+	FindReferencedUrls(ctx context.Context, urls []string) ([]string, error)
+	// IsKeyReferenced mencari pemakaian file berdasarkan key di storage tanpa ekstensi,
+	// untuk url yang ditulis berbeda dari hasil listing storage, misalnya versi dan ekstensi di url cloudinary
+	IsKeyReferenced(ctx context.Context, key string) (bool, error)
+}
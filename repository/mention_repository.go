@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+type MentionRepository interface {
+	// ReplaceByPhotoId mengganti semua mention di caption foto dengan mentions
+	ReplaceByPhotoId(ctx context.Context, photoId string, mentions []domain.Mention) ([]domain.Mention, error)
+	// ReplaceByCommentId mengganti semua mention di comment dengan mentions
+	ReplaceByCommentId(ctx context.Context, commentId uint, mentions []domain.Mention) ([]domain.Mention, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+type PhotoMediaRepository interface {
+	FindByPhotoId(ctx context.Context, photoId string) ([]domain.PhotoMedia, error)
+	// FindSimilar mencari foto lain yang punya slide dengan jarak hamming <= maxDistance dari hash
+	FindSimilar(ctx context.Context, hash int64, maxDistance int, excludePhotoId string, limit int) ([]domain.SimilarPhoto, error)
+	// HasSimilarByUser memeriksa apakah userId sudah memposting gambar yang mirip sejak since
+	HasSimilarByUser(ctx context.Context, userId uint, hash int64, maxDistance int, since time.Time) (bool, error)
+}
@@ -4,17 +4,28 @@ import (
 	"context"
 
 	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type PhotoRepository interface {
 	Create(ctx context.Context, photo domain.Photo) (domain.Photo, error)
 	FindById(ctx context.Context, id string) (domain.Photo, error)
-	FindAll(ctx context.Context) ([]domain.Photo, error)
-	FindByUserId(ctx context.Context, id uint) ([]domain.Photo, error)
+	FindAll(ctx context.Context, page helpers.PageRequest) ([]domain.Photo, error)
+	FindByUserId(ctx context.Context, id uint, page helpers.PageRequest) ([]domain.Photo, error)
+	FindByFollowerId(ctx context.Context, followerId uint, page helpers.PageRequest) ([]domain.Photo, error)
+	FindByUserIds(ctx context.Context, userIds []uint, page helpers.PageRequest) ([]domain.Photo, error)
+	FindByTagId(ctx context.Context, tagId uint, page helpers.PageRequest) ([]domain.Photo, error)
 	FindByIdAndByUserId(ctx context.Context, id string, userId uint) (*domain.Photo, error)
-	Update(ctx context.Context, photo domain.Photo, id string) (domain.Photo, error)
+	// Update menyimpan caption, cover, urutan photo.Media dan mengganti tag foto dengan tagIds
+	Update(ctx context.Context, photo domain.Photo, id string, tagIds []uint) (domain.Photo, error)
 	Delete(ctx context.Context, photo domain.Photo) error
 	IsPhotoExist(ctx context.Context, id string) error
 	FindPhotosByIDList(ctx context.Context, photoIds []string) ([]domain.Photo, error)
 	CountPhotoByUserId(ctx context.Context, userId uint) (int64, error)
+	// FindRecentEntriesByUserId hanya mengambil id dan created_at foto terbaru milik user,
+	// dipakai untuk mengisi timeline redis
+	FindRecentEntriesByUserId(ctx context.Context, userId uint, limit int) ([]domain.Photo, error)
+	// FindRecentEntriesByFollowerId sama seperti FindRecentEntriesByUserId untuk semua user yang di follow followerId
+	FindRecentEntriesByFollowerId(ctx context.Context, followerId uint, limit int) ([]domain.Photo, error)
+	FindIdsByUserId(ctx context.Context, userId uint) ([]string, error)
 }
@@ -9,4 +9,5 @@ type PhotoTagsRepository interface {
 	Add(ctx context.Context, photoTags domain.PhotoTags) error
 	Delete(ctx context.Context, photoId string) error
 	FindPhotoTagsByPhotoId(ctx context.Context, photoId string) ([]domain.PhotoTags, error)
+	CountByTagId(ctx context.Context, tagId uint) (int64, error)
 }
@@ -5,7 +5,37 @@ import (
 	"time"
 )
 
+// ZMember adalah satu anggota sorted set beserta score-nya
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
 type RedisRepository interface {
 	Set(ctx context.Context, key, value string, ttl time.Duration) error
 	Get(ctx context.Context, key string) (interface{}, error)
+	// MGet mengambil beberapa key sekaligus, key yang tidak ada bernilai nil
+	MGet(ctx context.Context, keys ...string) ([]interface{}, error)
+	Del(ctx context.Context, keys ...string) error
+	// SetNX hanya menyimpan value kalau key belum ada, dipakai sebagai lock sederhana
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// DelIfValue dan ExpireIfValue hanya mengubah key kalau isinya masih value,
+	// dipakai untuk melepas dan memperpanjang lock milik pemegang token tertentu
+	DelIfValue(ctx context.Context, key, value string) (bool, error)
+	ExpireIfValue(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Incr menaikkan counter dan memasang ttl saat counter baru dibuat
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// ZAddCapped menambahkan member ke beberapa sorted set sekaligus dan hanya menyimpan
+	// maxLength member dengan score tertinggi di setiap key
+	ZAddCapped(ctx context.Context, keys []string, member ZMember, maxLength int64) error
+	// ZAddManyCapped sama seperti ZAddCapped tapi untuk banyak member di satu key
+	ZAddManyCapped(ctx context.Context, key string, members []ZMember, maxLength int64) error
+	ZRem(ctx context.Context, keys []string, member string) error
+	ZRemMany(ctx context.Context, key string, members []string) error
+	// ZRevRangeByScore mengambil member dengan score di antara min dan max, diurutkan dari score tertinggi.
+	// min dan max mengikuti format redis, misalnya "-inf" atau "(123" untuk batas eksklusif. count 0 berarti tanpa batas
+	ZRevRangeByScore(ctx context.Context, key, min, max string, count int64) ([]ZMember, error)
+	ZCard(ctx context.Context, key string) (int64, error)
+	// XAdd menambahkan entry ke stream dan memangkas stream sampai kira-kira maxLength entry
+	XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLength int64) error
 }
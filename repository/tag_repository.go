@@ -2,12 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/ariwiraa/my-gram/domain"
 )
 
 type TagRepository interface {
 	Add(ctx context.Context, tag domain.Tag) (*domain.Tag, error)
-	FindByName(ctx context.Context, name string) ([]domain.Tag, error)
+	// FindOneByName mencari tag dengan nama yang sudah dinormalisasi secara persis
+	FindOneByName(ctx context.Context, name string) (*domain.Tag, error)
 	FindById(ctx context.Context, id uint) (*domain.Tag, error)
 	AddTagIfNotExists(ctx context.Context, name string) (*domain.Tag, error)
+	// SearchByPrefix mencari tag yang namanya diawali prefix, diurutkan dari yang paling banyak dipakai
+	SearchByPrefix(ctx context.Context, prefix string, limit int) ([]domain.TagUsage, error)
+	// FindTrending mengurutkan tag berdasarkan pertumbuhan pemakaian di window terakhir
+	// dibanding window sebelumnya, tag dengan pemakaian di bawah minRecentPhotos dilewati
+	FindTrending(ctx context.Context, now time.Time, window time.Duration, minRecentPhotos int64, limit int) ([]domain.TrendingTag, error)
 }
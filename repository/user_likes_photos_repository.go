@@ -12,4 +12,5 @@ type UserLikesPhotoRepository interface {
 	FindPhotoWhoLiked(ctx context.Context, photoId string) (*domain.Photo, error)
 	FindUserWhoLiked(ctx context.Context, userId uint) (*domain.User, error)
 	CountUsersWhoLikedPhotoByPhotoId(ctx context.Context, photoId string) (int64, error)
+	CountLikesByPhotoIds(ctx context.Context, photoIds []string) (map[string]int64, error)
 }
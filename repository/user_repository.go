@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/helpers"
@@ -16,10 +17,16 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindById(ctx context.Context, id uint) (*domain.User, error)
 	FindUsersByIDList(ctx context.Context, id []uint) ([]domain.User, error)
+	// FindIdsByUsernames hanya mengambil id dan username, username yang tidak ada tidak ikut dikembalikan
+	FindIdsByUsernames(ctx context.Context, usernames []string) ([]domain.User, error)
 	IsUsernameExists(ctx context.Context, username string) (bool, error)
 	IsEmailExists(ctx context.Context, email string) (bool, error)
 	IsUserExists(ctx context.Context, id uint) error
 	UpdateUser(ctx context.Context, user domain.User) error
+	FindAll(ctx context.Context, page helpers.PageRequest) ([]domain.User, error)
+	UpdateSuspendedAt(ctx context.Context, id uint, suspendedAt *time.Time) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	DeleteUser(ctx context.Context, id uint) error
 }
 
 type userRepository struct {
@@ -30,6 +37,116 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+// FindAll implements UserRepository.
+func (r *userRepository) FindAll(ctx context.Context, page helpers.PageRequest) ([]domain.User, error) {
+	var users []domain.User
+
+	query := r.db.WithContext(ctx)
+	if page.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", page.Cursor.CreatedAt, page.Cursor.UintId())
+	}
+
+	err := query.Order("created_at DESC").Order("id DESC").Limit(page.Limit + 1).Find(&users).Error
+	if err != nil {
+		log.Printf("[FindAll] with error detail %v", err.Error())
+		return users, helpers.ErrRepository
+	}
+
+	return users, nil
+}
+
+// UpdateSuspendedAt implements UserRepository.
+// Memakai Update bukan Updates supaya nilai nil tetap disimpan saat suspend dicabut
+func (r *userRepository) UpdateSuspendedAt(ctx context.Context, id uint, suspendedAt *time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("suspended_at", suspendedAt).Error
+	if err != nil {
+		log.Printf("[UpdateSuspendedAt] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// UpdateRole implements UserRepository.
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("role", role).Error
+	if err != nil {
+		log.Printf("[UpdateRole] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// DeleteUser implements UserRepository.
+// Semua data milik user dan data orang lain yang menempel di foto user ikut dihapus.
+// Baris media dan slide ikut hilang sehingga file-nya tidak lagi direferensikan dan dibersihkan media reaper
+func (r *userRepository) DeleteUser(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var photoIds []string
+		err := tx.Model(&domain.Photo{}).Where("user_id = ?", id).Pluck("id", &photoIds).Error
+		if err != nil {
+			return err
+		}
+
+		if len(photoIds) > 0 {
+			if err := tx.Where("photo_id IN ?", photoIds).Delete(&domain.UserLikesPhoto{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("photo_id IN ?", photoIds).Delete(&domain.Comment{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("photo_id IN ?", photoIds).Delete(&domain.PhotoTags{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", photoIds).Delete(&domain.Photo{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("user_id = ?", id).Delete(&domain.UserLikesPhoto{}).Error; err != nil {
+			return err
+		}
+		// total_likes comment yang disukai user dikurangi sebelum like-nya dihapus
+		likedComments := tx.Model(&domain.CommentLike{}).Select("comment_id").Where("user_id = ?", id)
+		err = tx.Model(&domain.Comment{}).
+			Where("id IN (?) AND total_likes > 0", likedComments).
+			UpdateColumn("total_likes", gorm.Expr("total_likes - 1")).
+			Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.CommentLike{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("follower_id = ? OR following_id = ?", id, id).Delete(&domain.Follow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Media{}).Error; err != nil {
+			return err
+		}
+		// semua sesi login ikut dihapus supaya refresh token user tidak bisa dipakai lagi
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Authentication{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&domain.User{}).Error
+	})
+
+	if err != nil {
+		log.Printf("[DeleteUser] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
 // UpdateUser implements UserRepository.
 func (r *userRepository) UpdateUser(ctx context.Context, user domain.User) error {
 	err := r.db.WithContext(ctx).Where("id = ?", user.ID).Updates(&user).Error
@@ -101,6 +218,22 @@ func (r *userRepository) FindUsersByIDList(ctx context.Context, userIds []uint)
 	return users, nil
 }
 
+// FindIdsByUsernames implements UserRepository
+func (r *userRepository) FindIdsByUsernames(ctx context.Context, usernames []string) ([]domain.User, error) {
+	var users []domain.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Select("id", "username").Where("username IN ?", usernames).Find(&users).Error
+	if err != nil {
+		log.Printf("[FindIdsByUsernames] with error detail %v", err.Error())
+		return users, helpers.ErrRepository
+	}
+
+	return users, nil
+}
+
 // IsEmailExists implements UserRepository
 func (r *userRepository) IsEmailExists(ctx context.Context, email string) (bool, error) {
 	var user domain.User
@@ -1,9 +1,13 @@
 package routes
 
 import (
+	"expvar"
+
 	_ "github.com/ariwiraa/my-gram/docs"
+	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/handler"
 	"github.com/ariwiraa/my-gram/middlewares"
+	"github.com/ariwiraa/my-gram/usecase"
 	"github.com/gin-gonic/gin"
 
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -17,7 +21,12 @@ type RouterHandler struct {
 	CommentHandler    handler.CommentHandler
 	LikesHandler      handler.UserLikesPhotosHandler
 	FollowsHandler    handler.FollowHandler
+	FeedHandler       handler.FeedHandler
+	TagHandler        handler.TagHandler
 	UserHandler       handler.UserHandler
+	AdminHandler      handler.AdminHandler
+	SessionHandler    handler.SessionHandler
+	JwksHandler       handler.JwksHandler
 	UploadFileHandler handler.UploadFileHandler
 }
 
@@ -34,28 +43,46 @@ type RouterHandler struct {
 // @in                          header
 // @name                        Authorization
 // @description	How to input in swagger : 'Bearer <insert_your_token_here>'
-func NewRouter(routerHandler RouterHandler) *gin.Engine {
+func NewRouter(routerHandler RouterHandler, tokenDenylistUsecase usecase.TokenDenylistUsecase) *gin.Engine {
 	router := gin.Default()
+	authentication := middlewares.Authentication(tokenDenylistUsecase)
 
 	router.POST("/signup", routerHandler.AuthHandler.PostUserRegisterHandler)
 	router.GET("/verify-email", routerHandler.AuthHandler.VerifyEmail)
 	router.POST("/resend-email", routerHandler.AuthHandler.ResendEmail)
 	router.POST("/signin", routerHandler.AuthHandler.PostUserLoginHandler)
+	router.POST("/password/forgot", routerHandler.AuthHandler.ForgotPasswordHandler)
+	router.POST("/password/reset", routerHandler.AuthHandler.ResetPasswordHandler)
 	router.PUT("/refresh", routerHandler.AuthHandler.PutAccessTokenHandler)
-	router.DELETE("/signout", middlewares.Authentication(), routerHandler.AuthHandler.LogoutHandler)
-	router.POST("/files/upload", middlewares.Authentication(), routerHandler.UploadFileHandler.UploadFileHandler)
+	router.DELETE("/signout", authentication, routerHandler.AuthHandler.LogoutHandler)
+	router.POST("/files/upload", authentication, routerHandler.UploadFileHandler.UploadFileHandler)
+
+	uploads := router.Group("/files/uploads")
+	{
+		uploads.OPTIONS("", routerHandler.UploadFileHandler.OptionsUploadHandler)
+		uploads.POST("", authentication, routerHandler.UploadFileHandler.PostUploadSessionHandler)
+		uploads.HEAD("/:id", authentication, routerHandler.UploadFileHandler.HeadUploadSessionHandler)
+		uploads.PATCH("/:id", authentication, routerHandler.UploadFileHandler.PatchUploadSessionHandler)
+		uploads.PUT("/:id", authentication, routerHandler.UploadFileHandler.PatchUploadSessionHandler)
+		uploads.POST("/:id/finalize", authentication, routerHandler.UploadFileHandler.PostFinalizeUploadHandler)
+		uploads.DELETE("/:id", authentication, routerHandler.UploadFileHandler.DeleteUploadSessionHandler)
+	}
+	router.POST("/files/presigned", authentication, routerHandler.UploadFileHandler.PostPresignedUploadHandler)
+	router.POST("/files/presigned/confirm", authentication, routerHandler.UploadFileHandler.PostConfirmUploadHandler)
+	router.GET("/.well-known/jwks.json", routerHandler.JwksHandler.GetJwksHandler)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	photo := router.Group("/photos")
 	{
 		// Photo
-		photo.Use(middlewares.Authentication())
+		photo.Use(authentication)
 		photo.POST("", routerHandler.PhotoHandler.PostPhotoHandler)
 		photo.GET("/all", routerHandler.PhotoHandler.GetPhotosHandler)
 		photo.GET("", routerHandler.PhotoHandler.GetPhotosByUserIdHandler)
 		photo.GET("/:id", routerHandler.PhotoHandler.GetPhotoHandler)
 		photo.PUT("/:id", routerHandler.PhotoHandler.PutPhotoHandler)
 		photo.DELETE("/:id", routerHandler.PhotoHandler.DeletePhotoHandler)
+		photo.GET("/:id/similar", middlewares.RequireRole(domain.RoleModerator, domain.RoleAdmin), routerHandler.PhotoHandler.GetSimilarPhotosHandler)
 
 		// Likes Photo
 		photo.POST("/:id/likes", routerHandler.LikesHandler.PostLikesHandler)
@@ -65,19 +92,27 @@ func NewRouter(routerHandler RouterHandler) *gin.Engine {
 		photo.POST("/:id/comments", routerHandler.CommentHandler.PostCommentHandler)
 		photo.GET("/:id/comments", routerHandler.CommentHandler.GetCommentsHandler)
 		photo.GET("/:id/comments/:commentId", routerHandler.CommentHandler.GetCommentHandler)
+		photo.GET("/:id/comments/:commentId/replies", routerHandler.CommentHandler.GetRepliesHandler)
+		photo.POST("/:id/comments/:commentId/likes", routerHandler.CommentHandler.PostCommentLikesHandler)
 		photo.PUT("/:id/comments/:commentId", routerHandler.CommentHandler.PutCommentHandler)
 		photo.DELETE("/:id/comments/:commentId", routerHandler.CommentHandler.DeleteCommentHandler)
 	}
 
 	me := router.Group("/me")
 	{
-		me.Use(middlewares.Authentication())
+		me.Use(authentication)
 		me.GET("/liked/photos", routerHandler.LikesHandler.GetPhotosLikedHandler)
+		me.GET("/feed", routerHandler.FeedHandler.GetFeedHandler)
+
+		// Sessions
+		me.GET("/sessions", routerHandler.SessionHandler.GetSessionsHandler)
+		me.DELETE("/sessions/:id", routerHandler.SessionHandler.DeleteSessionHandler)
+		me.DELETE("/sessions", routerHandler.SessionHandler.DeleteSessionsHandler)
 	}
 
 	users := router.Group("/users")
 	{
-		users.Use(middlewares.Authentication())
+		users.Use(authentication)
 		// Follow
 		users.POST("/:id/follows", routerHandler.FollowsHandler.PostFollowHandler)
 		users.GET("/:username/followers", routerHandler.FollowsHandler.GetFollowersHandler)
@@ -87,5 +122,33 @@ func NewRouter(routerHandler RouterHandler) *gin.Engine {
 		users.GET("/profile/:username", routerHandler.UserHandler.GetUserProfileHandler)
 	}
 
+	tags := router.Group("/tags")
+	{
+		tags.Use(authentication)
+		tags.GET("/search", routerHandler.TagHandler.SearchTagsHandler)
+		tags.GET("/trending", routerHandler.TagHandler.GetTrendingTagsHandler)
+		// tag bernama search atau trending diakses dengan prefix # (%23), handler membuang # sebelum mencari tag
+		tags.GET("/:name", routerHandler.TagHandler.GetTagHandler)
+		tags.GET("/:name/photos", routerHandler.TagHandler.GetTagPhotosHandler)
+	}
+
+	admin := router.Group("/admin")
+	{
+		admin.Use(authentication, middlewares.RequireRole(domain.RoleModerator, domain.RoleAdmin))
+		// Users
+		admin.GET("/users", routerHandler.AdminHandler.GetUsersHandler)
+		admin.PUT("/users/:id/suspend", routerHandler.AdminHandler.SuspendUserHandler)
+		admin.DELETE("/users/:id/suspend", routerHandler.AdminHandler.UnsuspendUserHandler)
+		admin.PUT("/users/:id/role", middlewares.RequireRole(domain.RoleAdmin), routerHandler.AdminHandler.PutUserRoleHandler)
+		admin.DELETE("/users/:id", middlewares.RequireRole(domain.RoleAdmin), routerHandler.AdminHandler.DeleteUserHandler)
+
+		// Content
+		admin.DELETE("/photos/:id", routerHandler.PhotoHandler.DeletePhotoHandler)
+		admin.DELETE("/photos/:id/comments/:commentId", routerHandler.CommentHandler.DeleteCommentHandler)
+
+		// Metrics, counter expvar seperti hasil media reaper
+		admin.GET("/metrics", middlewares.RequireRole(domain.RoleAdmin), gin.WrapH(expvar.Handler()))
+	}
+
 	return router
 }
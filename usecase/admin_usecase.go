@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+)
+
+type AdminUsecase interface {
+	GetUsers(ctx context.Context, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error)
+	SuspendUser(ctx context.Context, id uint, actor domain.Actor) error
+	UnsuspendUser(ctx context.Context, id uint, actor domain.Actor) error
+	ChangeRole(ctx context.Context, id uint, role string, actor domain.Actor) error
+	DeleteUser(ctx context.Context, id uint, actor domain.Actor) error
+}
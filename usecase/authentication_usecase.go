@@ -9,11 +9,16 @@ import (
 )
 
 type AuthenticationUsecase interface {
-	Add(ctx context.Context, token string) error
 	ExistsByRefreshToken(ctx context.Context, token string) error
+	RefreshAccessToken(ctx context.Context, payload request.RefreshTokenRequest) (*response.LoginResponse, error)
 	Delete(ctx context.Context, token string) error
 	Register(ctx context.Context, payload request.UserRegister) (*domain.User, error)
 	Login(ctx context.Context, payload request.UserLogin) (*response.LoginResponse, error)
 	VerifyEmail(ctx context.Context, email, token string) error
 	ResendEmail(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, payload request.ResetPasswordRequest) error
+	GetSessions(ctx context.Context, userId uint, currentSessionId string) ([]response.SessionResponse, error)
+	RevokeSession(ctx context.Context, userId uint, sessionId string) error
+	RevokeAllSessions(ctx context.Context, userId uint) error
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type CommentUsecase interface {
 	Create(ctx context.Context, payload request.CommentRequest) (*domain.Comment, error)
-	GetById(ctx context.Context, id uint, photoId string) (*domain.Comment, error)
-	GetAllCommentsByPhotoId(ctx context.Context, photoId string) ([]domain.Comment, error)
+	GetById(ctx context.Context, id uint, photoId string, userId uint) (*domain.Comment, error)
+	GetAllCommentsByPhotoId(ctx context.Context, photoId string, userId uint, sort string, page helpers.PageRequest) ([]domain.Comment, helpers.PageInfo, error)
+	GetReplies(ctx context.Context, photoId string, commentId uint, userId uint, page helpers.PageRequest) ([]domain.Comment, helpers.PageInfo, error)
+	LikeTheComment(ctx context.Context, photoId string, commentId uint, userId uint) (string, error)
 	Update(ctx context.Context, payload request.CommentRequest, id uint) (*domain.Comment, error)
-	Delete(ctx context.Context, id uint, photoId string)
+	Delete(ctx context.Context, id uint, photoId string, actor domain.Actor) error
 }
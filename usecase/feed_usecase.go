@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
+)
+
+type FeedUsecase interface {
+	GetHomeFeed(ctx context.Context, userId uint, page helpers.PageRequest) ([]response.PhotoResponse, helpers.PageInfo, error)
+}
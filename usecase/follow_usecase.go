@@ -4,10 +4,11 @@ import (
 	"context"
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type FollowUsecase interface {
 	FollowUser(ctx context.Context, followRequest request.FollowRequest) (string, error)
-	GetFollowersByUsername(ctx context.Context, username string) ([]domain.User, error)
-	GetFollowingsByUsername(ctx context.Context, username string) ([]domain.User, error)
+	GetFollowersByUsername(ctx context.Context, username string, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error)
+	GetFollowingsByUsername(ctx context.Context, username string, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error)
 }
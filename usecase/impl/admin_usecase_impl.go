@@ -0,0 +1,186 @@
+package impl
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+type adminUsecaseImpl struct {
+	userRepository       repository.UserRepository
+	photoRepository      repository.PhotoRepository
+	followRepository     repository.FollowRepository
+	tokenDenylistUsecase usecase.TokenDenylistUsecase
+	timelineUsecase      usecase.TimelineUsecase
+}
+
+func NewAdminUsecaseImpl(userRepository repository.UserRepository, photoRepository repository.PhotoRepository, followRepository repository.FollowRepository, tokenDenylistUsecase usecase.TokenDenylistUsecase, timelineUsecase usecase.TimelineUsecase) usecase.AdminUsecase {
+	return &adminUsecaseImpl{
+		userRepository:       userRepository,
+		photoRepository:      photoRepository,
+		followRepository:     followRepository,
+		tokenDenylistUsecase: tokenDenylistUsecase,
+		timelineUsecase:      timelineUsecase,
+	}
+}
+
+// GetUsers implements usecase.AdminUsecase.
+func (u *adminUsecaseImpl) GetUsers(ctx context.Context, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	users, err := u.userRepository.FindAll(ctx, page)
+	if err != nil {
+		log.Printf("[GetUsers, FindAll] with error detail %v", err.Error())
+		return []domain.User{}, helpers.PageInfo{}, err
+	}
+
+	users, pageInfo := helpers.Paginate(users, page.Limit, userCursor)
+
+	return users, pageInfo, nil
+}
+
+// SuspendUser implements usecase.AdminUsecase.
+func (u *adminUsecaseImpl) SuspendUser(ctx context.Context, id uint, actor domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := u.findModeratedUser(ctx, id, actor); err != nil {
+		return err
+	}
+
+	suspendedAt := time.Now()
+	err := u.userRepository.UpdateSuspendedAt(ctx, id, &suspendedAt)
+	if err != nil {
+		log.Printf("[SuspendUser, UpdateSuspendedAt] with error detail %v", err.Error())
+		return err
+	}
+
+	// access token yang masih berlaku ikut dicabut, refresh token sudah ditolak saat status suspend dicek
+	err = u.tokenDenylistUsecase.RevokeUserTokens(ctx, id)
+	if err != nil {
+		log.Printf("[SuspendUser, RevokeUserTokens] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// UnsuspendUser implements usecase.AdminUsecase.
+func (u *adminUsecaseImpl) UnsuspendUser(ctx context.Context, id uint, actor domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := u.findModeratedUser(ctx, id, actor); err != nil {
+		return err
+	}
+
+	err := u.userRepository.UpdateSuspendedAt(ctx, id, nil)
+	if err != nil {
+		log.Printf("[UnsuspendUser, UpdateSuspendedAt] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ChangeRole implements usecase.AdminUsecase.
+func (u *adminUsecaseImpl) ChangeRole(ctx context.Context, id uint, role string, actor domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !domain.IsValidRole(role) {
+		return helpers.ErrRoleInvalid
+	}
+
+	if _, err := u.findModeratedUser(ctx, id, actor); err != nil {
+		return err
+	}
+
+	err := u.userRepository.UpdateRole(ctx, id, role)
+	if err != nil {
+		log.Printf("[ChangeRole, UpdateRole] with error detail %v", err.Error())
+		return err
+	}
+
+	// role di access token lama sudah tidak berlaku, refresh token mengambil role baru dari database
+	err = u.tokenDenylistUsecase.RevokeUserTokens(ctx, id)
+	if err != nil {
+		log.Printf("[ChangeRole, RevokeUserTokens] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUser implements usecase.AdminUsecase.
+func (u *adminUsecaseImpl) DeleteUser(ctx context.Context, id uint, actor domain.Actor) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := u.findModeratedUser(ctx, id, actor); err != nil {
+		return err
+	}
+
+	// follower dan foto dicatat lebih dulu karena timeline baru dibersihkan setelah datanya terhapus
+	followerIds, err := u.followRepository.FindFollowerIdsByUserId(ctx, id)
+	if err != nil {
+		log.Printf("[DeleteUser, FindFollowerIdsByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	photoIds, err := u.photoRepository.FindIdsByUserId(ctx, id)
+	if err != nil {
+		log.Printf("[DeleteUser, FindIdsByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.userRepository.DeleteUser(ctx, id)
+	if err != nil {
+		log.Printf("[DeleteUser, DeleteUser] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.tokenDenylistUsecase.RevokeUserTokens(ctx, id)
+	if err != nil {
+		log.Printf("[DeleteUser, RevokeUserTokens] with error detail %v", err.Error())
+		return err
+	}
+
+	// file foto tidak dihapus di sini, setelah barisnya hilang file tersebut dibersihkan media reaper
+	err = u.timelineUsecase.RemoveUser(ctx, id, followerIds, photoIds)
+	if err != nil {
+		log.Printf("[DeleteUser, RemoveUser] with error detail %v", err.Error())
+	}
+
+	return nil
+}
+
+func (u *adminUsecaseImpl) findModeratedUser(ctx context.Context, id uint, actor domain.Actor) (*domain.User, error) {
+	user, err := u.userRepository.FindById(ctx, id)
+	if err != nil {
+		log.Printf("[findModeratedUser, FindById] with error detail %v", err.Error())
+		return nil, helpers.ErrUserNotFound
+	}
+
+	if err := authorizeUserModeration(*user, actor); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func userCursor(user domain.User) helpers.Cursor {
+	cursor := helpers.Cursor{Id: strconv.FormatUint(uint64(user.ID), 10)}
+	if user.CreatedAt != nil {
+		cursor.CreatedAt = *user.CreatedAt
+	}
+
+	return cursor
+}
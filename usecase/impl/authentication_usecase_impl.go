@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
@@ -12,19 +13,30 @@ import (
 	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/google/uuid"
+)
+
+const (
+	passwordResetTTL = 5 * time.Minute
+	// window berlaku untuk batas permintaan kode dan batas percobaan, minta kode baru tidak mengembalikan jatah percobaan
+	passwordResetWindow      = time.Hour
+	passwordResetMaxAttempts = 5
+	passwordResetMaxRequests = 3
 )
 
 type authenticationUsecaseImpl struct {
-	repo            repository.AuthenticationRepository
-	userRepository  repository.UserRepository
-	redisRepository repository.RedisRepository
+	repo                 repository.AuthenticationRepository
+	userRepository       repository.UserRepository
+	redisRepository      repository.RedisRepository
+	tokenDenylistUsecase usecase.TokenDenylistUsecase
 }
 
-func NewAuthenticationUsecaseImpl(repo repository.AuthenticationRepository, userRepository repository.UserRepository, redisRepository repository.RedisRepository) usecase.AuthenticationUsecase {
+func NewAuthenticationUsecaseImpl(repo repository.AuthenticationRepository, userRepository repository.UserRepository, redisRepository repository.RedisRepository, tokenDenylistUsecase usecase.TokenDenylistUsecase) usecase.AuthenticationUsecase {
 	return &authenticationUsecaseImpl{
-		repo:            repo,
-		userRepository:  userRepository,
-		redisRepository: redisRepository,
+		repo:                 repo,
+		userRepository:       userRepository,
+		redisRepository:      redisRepository,
+		tokenDenylistUsecase: tokenDenylistUsecase,
 	}
 }
 
@@ -65,6 +77,140 @@ func (u *authenticationUsecaseImpl) ResendEmail(ctx context.Context, email strin
 
 }
 
+// ForgotPassword implements usecase.AuthenticationUsecase.
+// Email yang tidak terdaftar tetap dianggap sukses supaya tidak bisa dipakai untuk menebak akun
+func (u *authenticationUsecaseImpl) ForgotPassword(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// dibatasi sebelum mencari user supaya email yang tidak terdaftar mendapat respon yang sama
+	requests, err := u.redisRepository.Incr(ctx, passwordResetRequestsKey(email), passwordResetWindow)
+	if err != nil {
+		log.Printf("[ForgotPassword, Incr] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	if requests > passwordResetMaxRequests {
+		return helpers.ErrPasswordResetLimited
+	}
+
+	user, err := u.userRepository.FindByEmail(ctx, email)
+	if err != nil {
+		log.Printf("[ForgotPassword, FindByEmail] with error detail %v", err.Error())
+		if err == helpers.ErrRepository {
+			return err
+		}
+		return nil
+	}
+
+	code, err := helpers.GenerateResetCode()
+	if err != nil {
+		log.Printf("[ForgotPassword, GenerateResetCode] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.redisRepository.Set(ctx, passwordResetKey(user.Email), code, passwordResetTTL)
+	if err != nil {
+		log.Printf("[ForgotPassword, Set] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	configMail := helpers.DataMail{
+		Username: user.Username,
+		Email:    user.Email,
+		Code:     code,
+		Subject:  "Your password reset code",
+	}
+
+	err = helpers.Mail(&configMail).Send()
+	if err != nil {
+		log.Printf("[ForgotPassword, Mail] with error detail %v", err.Error())
+		return helpers.ErrFailedSendEmail
+	}
+
+	return nil
+}
+
+// ResetPassword implements usecase.AuthenticationUsecase.
+func (u *authenticationUsecaseImpl) ResetPassword(ctx context.Context, payload request.ResetPasswordRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user, err := u.userRepository.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		log.Printf("[ResetPassword, FindByEmail] with error detail %v", err.Error())
+		if err == helpers.ErrRepository {
+			return err
+		}
+		return helpers.ErrResetCodeInvalid
+	}
+
+	// percobaan dihitung selama passwordResetWindow, bukan per kode, dan hanya direset saat password berhasil diganti
+	attempts, err := u.redisRepository.Incr(ctx, passwordResetAttemptsKey(user.Email), passwordResetWindow)
+	if err != nil {
+		log.Printf("[ResetPassword, Incr] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	if attempts > passwordResetMaxAttempts {
+		err = u.redisRepository.Del(ctx, passwordResetKey(user.Email))
+		if err != nil {
+			log.Printf("[ResetPassword, Del] with error detail %v", err.Error())
+		}
+		return helpers.ErrResetCodeInvalid
+	}
+
+	value, err := u.redisRepository.Get(ctx, passwordResetKey(user.Email))
+	if err != nil {
+		log.Printf("[ResetPassword, Get] with error detail %v", err.Error())
+		return helpers.ErrResetCodeInvalid
+	}
+
+	if value != payload.Code {
+		return helpers.ErrResetCodeInvalid
+	}
+
+	user.Password = helpers.HashPass(payload.Password)
+
+	err = u.userRepository.UpdateUser(ctx, *user)
+	if err != nil {
+		log.Printf("[ResetPassword, UpdateUser] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.redisRepository.Del(ctx, passwordResetKey(user.Email), passwordResetAttemptsKey(user.Email))
+	if err != nil {
+		log.Printf("[ResetPassword, Del] with error detail %v", err.Error())
+	}
+
+	// semua sesi lama harus login ulang dengan password baru
+	err = u.repo.DeleteByUserId(ctx, user.ID)
+	if err != nil {
+		log.Printf("[ResetPassword, DeleteByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.tokenDenylistUsecase.RevokeUserTokens(ctx, user.ID)
+	if err != nil {
+		log.Printf("[ResetPassword, RevokeUserTokens] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func passwordResetKey(email string) string {
+	return "password_reset:" + email
+}
+
+func passwordResetAttemptsKey(email string) string {
+	return "password_reset_attempts:" + email
+}
+
+func passwordResetRequestsKey(email string) string {
+	return "password_reset_requests:" + strings.ToLower(strings.TrimSpace(email))
+}
+
 // VerifyEmail implements usecase.AuthenticationUsecase.
 func (u *authenticationUsecaseImpl) VerifyEmail(ctx context.Context, email, token string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -173,63 +319,217 @@ func (u *authenticationUsecaseImpl) Login(ctx context.Context, payload request.U
 		return &response.LoginResponse{}, err
 	}
 
+	// password dicek lebih dulu supaya status akun tidak terbaca oleh orang yang tidak tahu password-nya
+	comparePassword := helpers.ComparePass([]byte(user.Password), []byte(payload.Password))
+	if !comparePassword {
+		log.Printf("[Login, ComparePass] password not match for user %d", user.ID)
+		return &response.LoginResponse{}, helpers.ErrPasswordNotMatch
+	}
+
 	if user.EmailVerificationAt == nil {
 		return &response.LoginResponse{}, helpers.ErrEmailNotVerified
 	}
 
-	comparePassword := helpers.ComparePass([]byte(user.Password), []byte(payload.Password))
-	if !comparePassword {
-		log.Printf("[Login, ComparePass] with error detail %v", err.Error())
-		return &response.LoginResponse{}, helpers.ErrPasswordNotMatch
+	if user.SuspendedAt != nil {
+		return &response.LoginResponse{}, helpers.ErrUserSuspended
+	}
+
+	// setiap login membuka family baru untuk device tersebut
+	familyId := uuid.NewString()
+	authentication := domain.Authentication{
+		RefreshToken: helpers.NewRefreshToken(uint64(user.ID), familyId).GenerateRefreshToken(),
+		UserId:       user.ID,
+		FamilyId:     familyId,
+		UserAgent:    payload.UserAgent,
+		IpAddress:    payload.IpAddress,
+		LastUsedAt:   time.Now(),
+	}
+
+	err = u.repo.Add(ctx, authentication)
+	if err != nil {
+		log.Printf("[Login, Add] with error detail %v", err.Error())
+		return &response.LoginResponse{}, err
 	}
 
 	loginResponse := response.LoginResponse{
-		AccessToken:  helpers.NewAccessToken(uint64(user.ID)).GenerateAccessToken(),
-		RefreshToken: helpers.NewRefreshToken(uint64(user.ID)).GenerateRefreshToken(),
+		AccessToken:  helpers.NewAccessToken(uint64(user.ID), user.Role, familyId).GenerateAccessToken(),
+		RefreshToken: authentication.RefreshToken,
 	}
 
 	return &loginResponse, nil
 }
 
-// Add implements usecase.AuthenticationUsecase.
-func (u *authenticationUsecaseImpl) Add(ctx context.Context, token string) error {
+// Delete implements usecase.AuthenticationUsecase.
+// Logout mencabut seluruh family supaya token hasil rotasi di device yang sama ikut mati
+func (u *authenticationUsecaseImpl) Delete(ctx context.Context, token string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	authentication := new(domain.Authentication)
+	authentication, err := u.repo.FindByRefreshToken(ctx, token)
+	if err != nil {
+		log.Printf("[Delete, FindByRefreshToken] with error detail %v", err.Error())
+		return err
+	}
 
-	authentication.RefreshToken = token
+	err = u.repo.DeleteByFamilyId(ctx, authentication.FamilyId)
+	if err != nil {
+		log.Printf("[Delete, DeleteByFamilyId] with error detail %v", err.Error())
+		return err
+	}
 
-	err := u.repo.Add(ctx, *authentication)
+	err = u.tokenDenylistUsecase.RevokeSessionTokens(ctx, authentication.FamilyId)
 	if err != nil {
-		log.Printf("[Add, Add] with error detail %v", err.Error())
+		log.Printf("[Delete, RevokeSessionTokens] with error detail %v", err.Error())
 		return err
 	}
 
 	return nil
+}
+
+// RefreshAccessToken implements usecase.AuthenticationUsecase.
+func (u *authenticationUsecaseImpl) RefreshAccessToken(ctx context.Context, payload request.RefreshTokenRequest) (*response.LoginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	refreshToken := payload.RefreshToken
+
+	claims, err := helpers.VerifyRefreshToken(refreshToken)
+	if err != nil {
+		log.Printf("[RefreshAccessToken, VerifyRefreshToken] with error detail %v", err.Error())
+		return nil, helpers.ErrTokenNotVerified
+	}
+
+	authentication, err := u.repo.FindByRefreshToken(ctx, refreshToken)
+	if err != nil {
+		log.Printf("[RefreshAccessToken, FindByRefreshToken] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	// token yang sudah dirotasi dipakai lagi, kemungkinan besar dicuri
+	if authentication.RotatedAt != nil {
+		log.Printf("[RefreshAccessToken] reused refresh token on family %s of user %d", authentication.FamilyId, authentication.UserId)
+		u.revokeFamily(ctx, authentication.FamilyId)
+		return nil, helpers.ErrRefreshTokenReused
+	}
+
+	// Role dan status suspend diambil dari database supaya perubahan langsung berlaku
+	user, err := u.userRepository.FindById(ctx, uint(claims.Id))
+	if err != nil {
+		log.Printf("[RefreshAccessToken, FindById] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, helpers.ErrUserSuspended
+	}
+
+	newAuthentication := domain.Authentication{
+		RefreshToken: helpers.NewRefreshToken(uint64(user.ID), authentication.FamilyId).GenerateRefreshToken(),
+		UserId:       user.ID,
+		FamilyId:     authentication.FamilyId,
+		UserAgent:    payload.UserAgent,
+		IpAddress:    payload.IpAddress,
+		CreatedAt:    authentication.CreatedAt,
+		LastUsedAt:   time.Now(),
+	}
 
+	err = u.repo.Rotate(ctx, refreshToken, newAuthentication)
+	if err != nil {
+		log.Printf("[RefreshAccessToken, Rotate] with error detail %v", err.Error())
+		if err == helpers.ErrRefreshTokenReused {
+			u.revokeFamily(ctx, authentication.FamilyId)
+		}
+		return nil, err
+	}
+
+	return &response.LoginResponse{
+		AccessToken:  helpers.NewAccessToken(uint64(user.ID), user.Role, authentication.FamilyId).GenerateAccessToken(),
+		RefreshToken: newAuthentication.RefreshToken,
+	}, nil
 }
 
-// Delete implements usecase.AuthenticationUsecase.
-func (u *authenticationUsecaseImpl) Delete(ctx context.Context, token string) error {
+// GetSessions implements usecase.AuthenticationUsecase.
+func (u *authenticationUsecaseImpl) GetSessions(ctx context.Context, userId uint, currentSessionId string) ([]response.SessionResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	authentication, err := u.repo.FindByRefreshToken(ctx, token)
+	authentications, err := u.repo.FindActiveByUserId(ctx, userId)
 	if err != nil {
-		log.Printf("[Delete, FindByRefreshToken] with error detail %v", err.Error())
+		log.Printf("[GetSessions, FindActiveByUserId] with error detail %v", err.Error())
+		return []response.SessionResponse{}, err
+	}
+
+	sessions := make([]response.SessionResponse, 0, len(authentications))
+	for _, authentication := range authentications {
+		sessions = append(sessions, response.SessionResponse{
+			Id:         authentication.FamilyId,
+			UserAgent:  authentication.UserAgent,
+			IpAddress:  authentication.IpAddress,
+			CreatedAt:  authentication.CreatedAt,
+			LastUsedAt: authentication.LastUsedAt,
+			Current:    authentication.FamilyId == currentSessionId,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession implements usecase.AuthenticationUsecase.
+func (u *authenticationUsecaseImpl) RevokeSession(ctx context.Context, userId uint, sessionId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(sessionId); err != nil {
+		return helpers.ErrSessionNotFound
+	}
+
+	err := u.repo.DeleteByUserIdAndFamilyId(ctx, userId, sessionId)
+	if err != nil {
+		log.Printf("[RevokeSession, DeleteByUserIdAndFamilyId] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.tokenDenylistUsecase.RevokeSessionTokens(ctx, sessionId)
+	if err != nil {
+		log.Printf("[RevokeSession, RevokeSessionTokens] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllSessions implements usecase.AuthenticationUsecase.
+func (u *authenticationUsecaseImpl) RevokeAllSessions(ctx context.Context, userId uint) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := u.repo.DeleteByUserId(ctx, userId)
+	if err != nil {
+		log.Printf("[RevokeAllSessions, DeleteByUserId] with error detail %v", err.Error())
 		return err
 	}
 
-	err = u.repo.Delete(ctx, *authentication)
+	err = u.tokenDenylistUsecase.RevokeUserTokens(ctx, userId)
 	if err != nil {
-		log.Printf("[Delete, Delete] with error detail %v", err.Error())
+		log.Printf("[RevokeAllSessions, RevokeUserTokens] with error detail %v", err.Error())
 		return err
 	}
 
 	return nil
 }
 
+func (u *authenticationUsecaseImpl) revokeFamily(ctx context.Context, familyId string) {
+	err := u.repo.DeleteByFamilyId(ctx, familyId)
+	if err != nil {
+		log.Printf("[revokeFamily, DeleteByFamilyId] with error detail %v", err.Error())
+	}
+
+	err = u.tokenDenylistUsecase.RevokeSessionTokens(ctx, familyId)
+	if err != nil {
+		log.Printf("[revokeFamily, RevokeSessionTokens] with error detail %v", err.Error())
+	}
+}
+
 func (u *authenticationUsecaseImpl) ExistsByRefreshToken(ctx context.Context, token string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -0,0 +1,331 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/google/uuid"
+)
+
+const (
+	storageRootFolder = "mygram-image"
+
+	// jumlah file dalam satu halaman List, sama dengan batas maksimal max_results admin API cloudinary
+	storageListPageSize = 500
+)
+
+type cloudinaryStorage struct {
+	cloud cloudinary.Cloudinary
+}
+
+func NewCloudinaryStorage(cloud cloudinary.Cloudinary) usecase.StorageProvider {
+	return &cloudinaryStorage{cloud: cloud}
+}
+
+// Remove implements usecase.StorageProvider.
+func (u *cloudinaryStorage) Remove(ctx context.Context, urlString string) (err error) {
+	publicId, err := cloudinaryPublicId(urlString)
+	if err != nil {
+		log.Printf("[Remove, cloudinaryPublicId] with error detail %v", err.Error())
+		return err
+	}
+
+	res, err := u.cloud.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicId,
+		ResourceType: cloudinaryResourceType(urlString),
+	})
+
+	if err != nil {
+		log.Printf("[Remove, Destroy] with error detail %v", err.Error())
+		return err
+	}
+
+	if strings.Contains(res.Result, "not found") {
+		return helpers.ErrFileNotFound
+	}
+
+	return err
+}
+
+// Upload implements usecase.StorageProvider.
+func (u *cloudinaryStorage) Upload(ctx context.Context, file io.Reader, pathDestination string) (uri string, err error) {
+	filename := uuid.NewString()
+
+	// auto membuat cloudinary menyimpan mp4 sebagai resource video
+	res, err := u.cloud.Upload.Upload(ctx, file, uploader.UploadParams{
+		PublicID:     getPublicId(pathDestination, filename),
+		ResourceType: "auto",
+	})
+
+	if err != nil {
+		log.Printf("[Upload, Upload] with error detail %v", err.Error())
+		return "", err
+	}
+
+	// file sudah diproses sebelum upload, jadi langsung pakai secure url tanpa transformasi
+	url := res.SecureURL
+
+	return url, nil
+}
+
+// Stat implements usecase.StorageProvider.
+func (u *cloudinaryStorage) Stat(ctx context.Context, urlString string) (*usecase.StorageObject, error) {
+	publicId, err := cloudinaryPublicId(urlString)
+	if err != nil {
+		log.Printf("[Stat, cloudinaryPublicId] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	res, err := u.cloud.Admin.Asset(ctx, admin.AssetParams{
+		PublicID:  publicId,
+		AssetType: api.AssetType(cloudinaryResourceType(urlString)),
+	})
+	if err != nil {
+		log.Printf("[Stat, Asset] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	if res.Error.Message != "" {
+		return nil, helpers.ErrFileNotFound
+	}
+
+	return &usecase.StorageObject{
+		Key:         res.PublicID,
+		URL:         urlString,
+		Size:        int64(res.Bytes),
+		ContentType: mime.TypeByExtension("." + res.Format),
+		UpdatedAt:   res.CreatedAt,
+	}, nil
+}
+
+// Open implements usecase.StorageProvider.
+// File diunduh dari secure url hasil admin API, bukan dari url kiriman client,
+// supaya server tidak bisa diarahkan mengunduh dari host lain
+func (u *cloudinaryStorage) Open(ctx context.Context, urlString string) (io.ReadCloser, error) {
+	publicId, err := cloudinaryPublicId(urlString)
+	if err != nil {
+		log.Printf("[Open, cloudinaryPublicId] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	res, err := u.cloud.Admin.Asset(ctx, admin.AssetParams{
+		PublicID:  publicId,
+		AssetType: api.AssetType(cloudinaryResourceType(urlString)),
+	})
+	if err != nil {
+		log.Printf("[Open, Asset] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	if res.Error.Message != "" {
+		return nil, helpers.ErrFileNotFound
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.SecureURL, nil)
+	if err != nil {
+		log.Printf("[Open, NewRequestWithContext] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("[Open, Do] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, helpers.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("cloudinary download failed with status %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// ObjectKey implements usecase.StorageProvider.
+// Key cloudinary adalah public id, yaitu path file tanpa ekstensi
+func (u *cloudinaryStorage) ObjectKey(urlString string) (string, error) {
+	return cloudinaryPublicId(urlString)
+}
+
+// List implements usecase.StorageProvider.
+// Admin API memisahkan daftar asset per resource type, jadi image dan video diambil bergantian
+func (u *cloudinaryStorage) List(ctx context.Context, fn func(objects []usecase.StorageObject) error) error {
+	for _, assetType := range []api.AssetType{api.Image, api.Video} {
+		nextCursor := ""
+		for {
+			res, err := u.cloud.Admin.Assets(ctx, admin.AssetsParams{
+				AssetType:    assetType,
+				DeliveryType: "upload",
+				Prefix:       storageRootFolder + "/",
+				MaxResults:   storageListPageSize,
+				NextCursor:   nextCursor,
+			})
+			if err != nil {
+				log.Printf("[List, Assets] with error detail %v", err.Error())
+				return err
+			}
+
+			if res.Error.Message != "" {
+				return errors.New(res.Error.Message)
+			}
+
+			objects := make([]usecase.StorageObject, 0, len(res.Assets))
+			for _, asset := range res.Assets {
+				objects = append(objects, usecase.StorageObject{
+					Key:         asset.PublicID,
+					URL:         asset.SecureURL,
+					Size:        int64(asset.Bytes),
+					ContentType: mime.TypeByExtension("." + asset.Format),
+					UpdatedAt:   asset.CreatedAt,
+				})
+			}
+
+			if len(objects) > 0 {
+				if err := fn(objects); err != nil {
+					return err
+				}
+			}
+
+			if res.NextCursor == "" {
+				break
+			}
+			nextCursor = res.NextCursor
+		}
+	}
+
+	return nil
+}
+
+// SignedURL implements usecase.StorageProvider.
+// Url cloudinary hanya bisa diberi signature, masa berlakunya butuh auth token key
+// yang belum dipakai di sini, jadi expiry diabaikan
+func (u *cloudinaryStorage) SignedURL(ctx context.Context, urlString string, expiry time.Duration) (string, error) {
+	publicId, err := cloudinaryPublicId(urlString)
+	if err != nil {
+		log.Printf("[SignedURL, cloudinaryPublicId] with error detail %v", err.Error())
+		return "", err
+	}
+
+	if cloudinaryResourceType(urlString) == api.Video {
+		video, err := u.cloud.Video(publicId)
+		if err != nil {
+			log.Printf("[SignedURL, Video] with error detail %v", err.Error())
+			return "", err
+		}
+
+		video.Config.URL.Secure = true
+		video.Config.URL.SignURL = true
+
+		return video.String()
+	}
+
+	image, err := u.cloud.Image(publicId)
+	if err != nil {
+		log.Printf("[SignedURL, Image] with error detail %v", err.Error())
+		return "", err
+	}
+
+	image.Config.URL.Secure = true
+	image.Config.URL.SignURL = true
+
+	return image.String()
+}
+
+// PresignUpload implements usecase.StorageProvider.
+// Signature cloudinary mengunci public id dan format file, tapi tidak bisa membatasi ukuran
+// dan selalu berlaku satu jam, jadi ukuran file diperiksa lagi saat konfirmasi
+func (u *cloudinaryStorage) PresignUpload(ctx context.Context, pathDestination, contentType string, maxSize int64, expiry time.Duration) (*usecase.PresignedUpload, error) {
+	publicId := getPublicId(pathDestination, uuid.NewString())
+	format := strings.TrimPrefix(helpers.FileExtension(contentType), ".")
+
+	resourceType := api.Image.String()
+	if contentType == "video/mp4" {
+		resourceType = api.Video
+	}
+
+	timestamp := time.Now()
+	params := url.Values{
+		"public_id":       {publicId},
+		"allowed_formats": {format},
+		"timestamp":       {strconv.FormatInt(timestamp.Unix(), 10)},
+	}
+
+	signature, err := api.SignParameters(params, u.cloud.Config.Cloud.APISecret)
+	if err != nil {
+		log.Printf("[PresignUpload, SignParameters] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	fields := map[string]string{
+		"api_key":   u.cloud.Config.Cloud.APIKey,
+		"signature": signature,
+	}
+	for key := range params {
+		fields[key] = params.Get(key)
+	}
+
+	cloudName := u.cloud.Config.Cloud.CloudName
+
+	return &usecase.PresignedUpload{
+		UploadURL: fmt.Sprintf("%s/v1_1/%s/%s/upload", api.BaseURL(u.cloud.Config.API.UploadPrefix), cloudName, resourceType),
+		Fields:    fields,
+		FileURL:   fmt.Sprintf("https://res.cloudinary.com/%s/%s/upload/%s.%s", cloudName, resourceType, publicId, format),
+		ExpiresAt: timestamp.Add(min(expiry, time.Hour)),
+	}, nil
+}
+
+func getPublicId(pathDestination, filename string) string {
+	return storageRootFolder + "/" + pathDestination + "/" + filename
+}
+
+// cloudinaryPublicId mengambil public id dari secure url, misalnya
+// https://res.cloudinary.com/<cloud>/image/upload/v1/mygram-image/1-images/<uuid>.jpg
+func cloudinaryPublicId(urlString string) (string, error) {
+	parsedURL, err := url.Parse(urlString)
+	if err != nil {
+		return "", err
+	}
+
+	index := strings.Index(parsedURL.Path, "/"+storageRootFolder+"/")
+	if index < 0 {
+		return "", helpers.ErrFileNotFound
+	}
+
+	imagePath := parsedURL.Path[index+1:]
+	directory, filename := path.Split(imagePath)
+	if filename == "" {
+		return "", errors.New("url does not point to a file")
+	}
+
+	// Ambil nama file tanpa ekstensi
+	return directory + strings.TrimSuffix(filename, filepath.Ext(filename)), nil
+}
+
+// cloudinaryResourceType membaca tipe resource dari url, video disajikan dari /video/upload/
+func cloudinaryResourceType(urlString string) string {
+	if strings.Contains(urlString, "/video/upload/") {
+		return api.Video
+	}
+
+	return api.Image.String()
+}
@@ -3,17 +3,22 @@ package impl
 import (
 	"context"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"github.com/ariwiraa/my-gram/usecase"
 )
 
 type commentUsecase struct {
-	commentRepository repository.CommentRepository
-	photoRepository   repository.PhotoRepository
+	commentRepository     repository.CommentRepository
+	commentLikeRepository repository.CommentLikeRepository
+	photoRepository       repository.PhotoRepository
+	mentionRepository     repository.MentionRepository
+	mentionUsecase        usecase.MentionUsecase
 }
 
 // Create implements CommentUsecase
@@ -35,57 +40,168 @@ func (u *commentUsecase) Create(ctx context.Context, payload request.CommentRequ
 		UserId:  payload.UserId,
 	}
 
+	if payload.ParentId != nil {
+		parent, err := u.findThreadParent(ctx, *payload.ParentId, payload.PhotoId)
+		if err != nil {
+			log.Printf("[Create, findThreadParent] with error detail %v", err.Error())
+			return &comment, err
+		}
+
+		comment.ParentId = &parent.ID
+	}
+
+	// mention ikut tersimpan bersama comment lewat asosiasi Mentions
+	comment.Mentions, err = u.mentionUsecase.Resolve(ctx, payload.Message)
+	if err != nil {
+		log.Printf("[Create, Resolve] with error detail %v", err.Error())
+		return &comment, err
+	}
+
 	newComment, err := u.commentRepository.Create(ctx, comment)
 	if err != nil {
 		log.Printf("[Create, Create] with error detail %v", err.Error())
 		return newComment, err
 	}
 
+	// gagal mengirim notifikasi tidak menggagalkan comment
+	event := domain.MentionEvent{AuthorId: newComment.UserId, PhotoId: newComment.PhotoId, CommentId: &newComment.ID, CreatedAt: time.Now()}
+	err = u.mentionUsecase.Publish(ctx, event, newComment.Mentions, nil)
+	if err != nil {
+		log.Printf("[Create, Publish] with error detail %v", err.Error())
+	}
+
 	return newComment, nil
 }
 
 // Delete implements CommentUsecase
-func (u *commentUsecase) Delete(ctx context.Context, id uint, photoId string) {
+func (u *commentUsecase) Delete(ctx context.Context, id uint, photoId string, actor domain.Actor) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err := u.photoRepository.IsPhotoExist(ctx, photoId)
+	photo, err := u.photoRepository.FindById(ctx, photoId)
 	if err != nil {
-		log.Printf("[Delete, IsPhotoExist] with error detail %v", err.Error())
-		return
+		log.Printf("[Delete, FindById] with error detail %v", err.Error())
+		return err
 	}
 
 	comment, err := u.commentRepository.FindById(ctx, id)
 	if err != nil {
 		log.Printf("[Delete, FindById] with error detail %v", err.Error())
-		return
+		return err
+	}
+
+	if comment.PhotoId != photo.ID {
+		return helpers.ErrCommentNotFound
+	}
+
+	// Pemilik foto boleh menghapus comment orang lain di fotonya
+	err = authorizeCommentModerator(*comment, photo, actor)
+	if err != nil {
+		log.Printf("[Delete, authorizeCommentModerator] user %d cannot delete comment %d", actor.Id, id)
+		return err
+	}
+
+	err = u.commentRepository.Delete(ctx, comment.ID)
+	if err != nil {
+		log.Printf("[Delete, Delete] with error detail %v", err.Error())
+		return err
 	}
 
-	u.commentRepository.Delete(ctx, comment.ID)
+	return nil
 }
 
 // GetAll implements CommentUsecase
-func (u *commentUsecase) GetAllCommentsByPhotoId(ctx context.Context, photoId string) ([]domain.Comment, error) {
+func (u *commentUsecase) GetAllCommentsByPhotoId(ctx context.Context, photoId string, userId uint, sort string, page helpers.PageRequest) ([]domain.Comment, helpers.PageInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	if sort == "" {
+		sort = domain.CommentSortNewest
+	}
+
+	if !domain.IsValidCommentSort(sort) {
+		return nil, helpers.PageInfo{}, helpers.ErrCommentSortInvalid
+	}
+
+	// cursor dari urutan newest tidak membawa score jadi tidak bisa dipakai untuk urutan top, begitu juga sebaliknya
+	if page.Cursor != nil && (page.Cursor.Score != nil) != (sort == domain.CommentSortTop) {
+		return nil, helpers.PageInfo{}, helpers.ErrCursorInvalid
+	}
+
 	err := u.photoRepository.IsPhotoExist(ctx, photoId)
 	if err != nil {
 		log.Printf("[GetAllCommentsByPhotoId, IsPhotoExist] with error detail %v", err.Error())
-		return nil, err
+		return nil, helpers.PageInfo{}, err
 	}
 
-	comments, err := u.commentRepository.FindAllCommentsByPhotoId(ctx, photoId)
+	comments, err := u.commentRepository.FindAllCommentsByPhotoId(ctx, photoId, sort, page)
 	if err != nil {
 		log.Printf("[GetAllCommentsByPhotoId, FindAllCommentsByPhotoId] with error detail %v", err.Error())
-		return comments, err
+		return comments, helpers.PageInfo{}, err
+	}
+
+	cursorOf := commentCursor
+	if sort == domain.CommentSortTop {
+		cursorOf = topCommentCursor
+	}
+
+	comments, pageInfo := helpers.Paginate(comments, page.Limit, cursorOf)
+
+	err = u.attachTotalReplies(ctx, comments)
+	if err != nil {
+		log.Printf("[GetAllCommentsByPhotoId, attachTotalReplies] with error detail %v", err.Error())
+		return comments, helpers.PageInfo{}, err
 	}
 
-	return comments, nil
+	err = u.attachLikedByMe(ctx, comments, userId)
+	if err != nil {
+		log.Printf("[GetAllCommentsByPhotoId, attachLikedByMe] with error detail %v", err.Error())
+		return comments, helpers.PageInfo{}, err
+	}
+
+	return comments, pageInfo, nil
+}
+
+// GetReplies implements CommentUsecase
+func (u *commentUsecase) GetReplies(ctx context.Context, photoId string, commentId uint, userId uint, page helpers.PageRequest) ([]domain.Comment, helpers.PageInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := u.photoRepository.IsPhotoExist(ctx, photoId)
+	if err != nil {
+		log.Printf("[GetReplies, IsPhotoExist] with error detail %v", err.Error())
+		return nil, helpers.PageInfo{}, err
+	}
+
+	parent, err := u.commentRepository.FindById(ctx, commentId)
+	if err != nil {
+		log.Printf("[GetReplies, FindById] with error detail %v", err.Error())
+		return nil, helpers.PageInfo{}, err
+	}
+
+	if parent.PhotoId != photoId {
+		return nil, helpers.PageInfo{}, helpers.ErrCommentNotFound
+	}
+
+	replies, err := u.commentRepository.FindRepliesByParentId(ctx, parent.ID, page)
+	if err != nil {
+		log.Printf("[GetReplies, FindRepliesByParentId] with error detail %v", err.Error())
+		return replies, helpers.PageInfo{}, err
+	}
+
+	replies, pageInfo := helpers.Paginate(replies, page.Limit, commentCursor)
+
+	err = u.attachLikedByMe(ctx, replies, userId)
+	if err != nil {
+		log.Printf("[GetReplies, attachLikedByMe] with error detail %v", err.Error())
+		return replies, helpers.PageInfo{}, err
+	}
+
+	return replies, pageInfo, nil
 }
 
 // GetById implements CommentUsecase
-func (u *commentUsecase) GetById(ctx context.Context, id uint, photoId string) (*domain.Comment, error) {
+func (u *commentUsecase) GetById(ctx context.Context, id uint, photoId string, userId uint) (*domain.Comment, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -101,7 +217,70 @@ func (u *commentUsecase) GetById(ctx context.Context, id uint, photoId string) (
 		return comment, err
 	}
 
-	return comment, nil
+	if comment.PhotoId != photoId {
+		return &domain.Comment{}, helpers.ErrCommentNotFound
+	}
+
+	comments := []domain.Comment{*comment}
+	err = u.attachTotalReplies(ctx, comments)
+	if err != nil {
+		log.Printf("[GetById, attachTotalReplies] with error detail %v", err.Error())
+		return comment, err
+	}
+
+	err = u.attachLikedByMe(ctx, comments, userId)
+	if err != nil {
+		log.Printf("[GetById, attachLikedByMe] with error detail %v", err.Error())
+		return comment, err
+	}
+
+	return &comments[0], nil
+}
+
+// LikeTheComment implements CommentUsecase
+func (u *commentUsecase) LikeTheComment(ctx context.Context, photoId string, commentId uint, userId uint) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := u.photoRepository.IsPhotoExist(ctx, photoId)
+	if err != nil {
+		log.Printf("[LikeTheComment, IsPhotoExist] with error detail %v", err.Error())
+		return "", err
+	}
+
+	comment, err := u.commentRepository.FindById(ctx, commentId)
+	if err != nil {
+		log.Printf("[LikeTheComment, FindById] with error detail %v", err.Error())
+		return "", err
+	}
+
+	if comment.PhotoId != photoId {
+		return "", helpers.ErrCommentNotFound
+	}
+
+	userLike, err := u.commentLikeRepository.VerifyUserLike(ctx, comment.ID, userId)
+	if err != nil {
+		log.Printf("[LikeTheComment, VerifyUserLike] with error detail %v", err.Error())
+		return "", err
+	}
+
+	if !userLike {
+		err = u.commentLikeRepository.InsertLike(ctx, domain.CommentLike{CommentId: comment.ID, UserId: userId})
+		if err != nil {
+			log.Printf("[LikeTheComment, InsertLike] with error detail %v", err.Error())
+			return "", err
+		}
+
+		return "Berhasil menyukai comment", nil
+	}
+
+	err = u.commentLikeRepository.DeleteLike(ctx, comment.ID, userId)
+	if err != nil {
+		log.Printf("[LikeTheComment, DeleteLike] with error detail %v", err.Error())
+		return "", err
+	}
+
+	return "Batal menyukai comment", nil
 }
 
 // Update implements CommentUsecase
@@ -115,7 +294,24 @@ func (u *commentUsecase) Update(ctx context.Context, payload request.CommentRequ
 		return comment, err
 	}
 
+	if comment.PhotoId != payload.PhotoId {
+		return comment, helpers.ErrCommentNotFound
+	}
+
+	err = authorizeCommentAuthor(*comment, payload.UserId)
+	if err != nil {
+		log.Printf("[Update, authorizeCommentAuthor] user %d is not the author of comment %d", payload.UserId, id)
+		return comment, err
+	}
+
 	comment.Message = payload.Message
+	previousMentions := comment.Mentions
+
+	mentions, err := u.mentionUsecase.Resolve(ctx, payload.Message)
+	if err != nil {
+		log.Printf("[Update, Resolve] with error detail %v", err.Error())
+		return comment, err
+	}
 
 	updatedComment, err := u.commentRepository.Update(ctx, *comment, id)
 	if err != nil {
@@ -123,12 +319,105 @@ func (u *commentUsecase) Update(ctx context.Context, payload request.CommentRequ
 		return updatedComment, err
 	}
 
+	updatedComment.Mentions, err = u.mentionRepository.ReplaceByCommentId(ctx, id, mentions)
+	if err != nil {
+		log.Printf("[Update, ReplaceByCommentId] with error detail %v", err.Error())
+		return updatedComment, err
+	}
+
+	// hanya user yang baru di-mention yang dinotifikasi
+	event := domain.MentionEvent{AuthorId: updatedComment.UserId, PhotoId: updatedComment.PhotoId, CommentId: &updatedComment.ID, CreatedAt: time.Now()}
+	err = u.mentionUsecase.Publish(ctx, event, updatedComment.Mentions, previousMentions)
+	if err != nil {
+		log.Printf("[Update, Publish] with error detail %v", err.Error())
+	}
+
 	return updatedComment, nil
 }
 
-func NewCommentUsecase(comment repository.CommentRepository, photoRepository repository.PhotoRepository) usecase.CommentUsecase {
+// findThreadParent mengembalikan comment level atas yang akan dibalas. Balasan untuk sebuah
+// balasan ditempelkan ke parent-nya supaya thread hanya satu level
+func (u *commentUsecase) findThreadParent(ctx context.Context, parentId uint, photoId string) (*domain.Comment, error) {
+	parent, err := u.commentRepository.FindById(ctx, parentId)
+	if err != nil {
+		return parent, err
+	}
+
+	if parent.PhotoId != photoId {
+		return parent, helpers.ErrCommentNotFound
+	}
+
+	if parent.ParentId != nil {
+		return u.commentRepository.FindById(ctx, *parent.ParentId)
+	}
+
+	return parent, nil
+}
+
+// attachTotalReplies mengisi jumlah balasan untuk comment level atas
+func (u *commentUsecase) attachTotalReplies(ctx context.Context, comments []domain.Comment) error {
+	var parentIds []uint
+	for _, comment := range comments {
+		if comment.ParentId == nil {
+			parentIds = append(parentIds, comment.ID)
+		}
+	}
+
+	if len(parentIds) == 0 {
+		return nil
+	}
+
+	totalReplies, err := u.commentRepository.CountRepliesByParentIds(ctx, parentIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].TotalReplies = totalReplies[comments[i].ID]
+	}
+
+	return nil
+}
+
+// attachLikedByMe menandai comment yang sudah disukai user yang sedang login
+func (u *commentUsecase) attachLikedByMe(ctx context.Context, comments []domain.Comment, userId uint) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	commentIds := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		commentIds = append(commentIds, comment.ID)
+	}
+
+	likedComments, err := u.commentLikeRepository.FindLikedCommentIds(ctx, userId, commentIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].LikedByMe = likedComments[comments[i].ID]
+	}
+
+	return nil
+}
+
+func commentCursor(comment domain.Comment) helpers.Cursor {
+	return helpers.Cursor{CreatedAt: *comment.CreatedAt, Id: strconv.FormatUint(uint64(comment.ID), 10)}
+}
+
+func topCommentCursor(comment domain.Comment) helpers.Cursor {
+	cursor := commentCursor(comment)
+	cursor.Score = &comment.TotalLikes
+	return cursor
+}
+
+func NewCommentUsecase(comment repository.CommentRepository, commentLike repository.CommentLikeRepository, photoRepository repository.PhotoRepository, mentionRepository repository.MentionRepository, mentionUsecase usecase.MentionUsecase) usecase.CommentUsecase {
 	return &commentUsecase{
-		commentRepository: comment,
-		photoRepository:   photoRepository,
+		commentRepository:     comment,
+		commentLikeRepository: commentLike,
+		photoRepository:       photoRepository,
+		mentionRepository:     mentionRepository,
+		mentionUsecase:        mentionUsecase,
 	}
 }
@@ -0,0 +1,169 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+var errTimelineUnavailable = errors.New("timeline unavailable")
+
+type feedUsecaseImpl struct {
+	photoRepository          repository.PhotoRepository
+	commentRepository        repository.CommentRepository
+	userLikesPhotoRepository repository.UserLikesPhotoRepository
+	timelineUsecase          usecase.TimelineUsecase
+}
+
+func NewFeedUsecaseImpl(photoRepository repository.PhotoRepository, commentRepository repository.CommentRepository, userLikesPhotoRepository repository.UserLikesPhotoRepository, timelineUsecase usecase.TimelineUsecase) usecase.FeedUsecase {
+	return &feedUsecaseImpl{
+		photoRepository:          photoRepository,
+		commentRepository:        commentRepository,
+		userLikesPhotoRepository: userLikesPhotoRepository,
+		timelineUsecase:          timelineUsecase,
+	}
+}
+
+// GetHomeFeed implements usecase.FeedUsecase.
+func (u *feedUsecaseImpl) GetHomeFeed(ctx context.Context, userId uint, page helpers.PageRequest) ([]response.PhotoResponse, helpers.PageInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	photos, err := u.findTimelinePhotos(ctx, userId, page)
+	if err != nil {
+		// Timeline redis hanya cache, jika tidak bisa dipakai feed dibangun dari database
+		log.Printf("[GetHomeFeed, findTimelinePhotos] fallback to database: %v", err.Error())
+
+		photos, err = u.photoRepository.FindByFollowerId(ctx, userId, page)
+		if err != nil {
+			log.Printf("[GetHomeFeed, FindByFollowerId] with error detail %v", err.Error())
+			return []response.PhotoResponse{}, helpers.PageInfo{}, err
+		}
+	}
+
+	photos, pageInfo := helpers.Paginate(photos, page.Limit, photoCursor)
+
+	feed, err := u.buildPhotoResponses(ctx, photos)
+	if err != nil {
+		return []response.PhotoResponse{}, helpers.PageInfo{}, err
+	}
+
+	return feed, pageInfo, nil
+}
+
+// findTimelinePhotos menggabungkan foto dari timeline redis (fan-out-on-write)
+// dengan foto dari user yang follower-nya melewati threshold (fan-out-on-read)
+func (u *feedUsecaseImpl) findTimelinePhotos(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Photo, error) {
+	photoIds, capped, err := u.timelineUsecase.GetPhotoIds(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(photoIds) == 0 && page.Cursor == nil {
+		return nil, errTimelineUnavailable
+	}
+
+	// Timeline sudah terpotong dan tidak cukup untuk satu halaman, sisanya hanya ada di database
+	if capped && len(photoIds) <= page.Limit {
+		return nil, errTimelineUnavailable
+	}
+
+	photos, err := u.photoRepository.FindPhotosByIDList(ctx, photoIds)
+	if err != nil {
+		return nil, err
+	}
+
+	fanOutOnReadUserIds, err := u.timelineUsecase.GetFanOutOnReadUserIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fanOutOnReadUserIds) > 0 {
+		popularPhotos, err := u.photoRepository.FindByUserIds(ctx, fanOutOnReadUserIds, page)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, popularPhotos...)
+	}
+
+	// User yang baru melewati threshold bisa punya foto di timeline dan di hasil query
+	seen := make(map[string]bool, len(photos))
+	uniquePhotos := photos[:0]
+	for _, photo := range photos {
+		if seen[photo.ID] {
+			continue
+		}
+		seen[photo.ID] = true
+		uniquePhotos = append(uniquePhotos, photo)
+	}
+	photos = uniquePhotos
+
+	sort.Slice(photos, func(i, j int) bool {
+		if photos[i].CreatedAt.Equal(*photos[j].CreatedAt) {
+			return photos[i].ID > photos[j].ID
+		}
+		return photos[i].CreatedAt.After(*photos[j].CreatedAt)
+	})
+
+	return photos, nil
+}
+
+// buildPhotoResponses menghitung total like dan comment untuk semua foto
+// dengan satu query per tabel, bukan satu query per foto
+func (u *feedUsecaseImpl) buildPhotoResponses(ctx context.Context, photos []domain.Photo) ([]response.PhotoResponse, error) {
+	photoIds := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		photoIds = append(photoIds, photo.ID)
+	}
+
+	totalComments, err := u.commentRepository.CountCommentsByPhotoIds(ctx, photoIds)
+	if err != nil {
+		log.Printf("[buildPhotoResponses, CountCommentsByPhotoIds] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	totalLikes, err := u.userLikesPhotoRepository.CountLikesByPhotoIds(ctx, photoIds)
+	if err != nil {
+		log.Printf("[buildPhotoResponses, CountLikesByPhotoIds] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	responses := make([]response.PhotoResponse, 0, len(photos))
+	for _, photo := range photos {
+		responsePhoto := response.PhotoResponse{
+			Id:            photo.ID,
+			Caption:       photo.Caption,
+			MediaType:     photo.MediaType,
+			PhotoUrl:      photo.PhotoUrl,
+			PosterUrl:     photo.PosterUrl,
+			MediumUrl:     photo.MediumUrl,
+			ThumbnailUrl:  photo.ThumbnailUrl,
+			Width:         photo.Width,
+			Height:        photo.Height,
+			BlurHash:      photo.BlurHash,
+			DominantColor: photo.DominantColor,
+			Media:         toPhotoMediaResponses(photoMediaOf(photo)),
+			Mentions:      photo.Mentions,
+			TotalLikes:    totalLikes[photo.ID],
+			TotalComments: totalComments[photo.ID],
+			Username:      photo.User.Username,
+			CreatedAt:     photo.CreatedAt,
+		}
+
+		for _, tag := range photo.Tags {
+			responsePhoto.PhotoTags = append(responsePhoto.PhotoTags, tag.Name)
+		}
+
+		responses = append(responses, responsePhoto)
+	}
+
+	return responses, nil
+}
@@ -3,10 +3,12 @@ package impl
 import (
 	"context"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"github.com/ariwiraa/my-gram/usecase"
 )
@@ -14,48 +16,72 @@ import (
 type followUsecaseImpl struct {
 	followRepository repository.FollowRepository
 	userRepository   repository.UserRepository
+	timelineUsecase  usecase.TimelineUsecase
 }
 
-func NewFollowUsecaseImpl(followRepository repository.FollowRepository, userRepository repository.UserRepository) usecase.FollowUsecase {
-	return &followUsecaseImpl{followRepository: followRepository, userRepository: userRepository}
+func NewFollowUsecaseImpl(followRepository repository.FollowRepository, userRepository repository.UserRepository, timelineUsecase usecase.TimelineUsecase) usecase.FollowUsecase {
+	return &followUsecaseImpl{followRepository: followRepository, userRepository: userRepository, timelineUsecase: timelineUsecase}
 }
 
-func (u *followUsecaseImpl) GetFollowingsByUsername(ctx context.Context, username string) ([]domain.User, error) {
+func (u *followUsecaseImpl) GetFollowingsByUsername(ctx context.Context, username string, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user, err := u.userRepository.FindByUsername(ctx, username)
 	if err != nil {
 		log.Printf("[GetFollowingByUsername, FindByUsername] with error detail %v", err.Error())
-		return []domain.User{}, err
+		return []domain.User{}, helpers.PageInfo{}, err
 	}
 
-	followings, err := u.followRepository.FindFollowingByUserId(ctx, user.ID)
+	follows, err := u.followRepository.FindFollowingByUserId(ctx, user.ID, page)
 	if err != nil {
 		log.Printf("[GetFollowingByUsername, FindFollowingByUserId] with error detail %v", err.Error())
-		return followings, err
+		return []domain.User{}, helpers.PageInfo{}, err
 	}
 
-	return followings, nil
+	follows, pageInfo := helpers.Paginate(follows, page.Limit, followCursor)
+
+	followings := make([]domain.User, 0, len(follows))
+	for _, follow := range follows {
+		followings = append(followings, follow.Following)
+	}
+
+	return followings, pageInfo, nil
 }
 
-func (u *followUsecaseImpl) GetFollowersByUsername(ctx context.Context, username string) ([]domain.User, error) {
+func (u *followUsecaseImpl) GetFollowersByUsername(ctx context.Context, username string, page helpers.PageRequest) ([]domain.User, helpers.PageInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user, err := u.userRepository.FindByUsername(ctx, username)
 	if err != nil {
 		log.Printf("[GetFollowersByUsername, FindByUsername] with error detail %v", err.Error())
-		return []domain.User{}, err
+		return []domain.User{}, helpers.PageInfo{}, err
 	}
 
-	followers, err := u.followRepository.FindFollowersByUserId(ctx, user.ID)
+	follows, err := u.followRepository.FindFollowersByUserId(ctx, user.ID, page)
 	if err != nil {
 		log.Printf("[GetFollowingByUsername, FindFollowersByUserId] with error detail %v", err.Error())
-		return followers, err
+		return []domain.User{}, helpers.PageInfo{}, err
+	}
+
+	follows, pageInfo := helpers.Paginate(follows, page.Limit, followCursor)
+
+	followers := make([]domain.User, 0, len(follows))
+	for _, follow := range follows {
+		followers = append(followers, follow.Follower)
+	}
+
+	return followers, pageInfo, nil
+}
+
+func followCursor(follow domain.Follow) helpers.Cursor {
+	cursor := helpers.Cursor{Id: strconv.FormatUint(uint64(follow.ID), 10)}
+	if follow.DateFollowed != nil {
+		cursor.CreatedAt = *follow.DateFollowed
 	}
 
-	return followers, nil
+	return cursor
 }
 
 func (u *followUsecaseImpl) FollowUser(ctx context.Context, followRequest request.FollowRequest) (string, error) {
@@ -84,6 +110,12 @@ func (u *followUsecaseImpl) FollowUser(ctx context.Context, followRequest reques
 		}
 		log.Printf("id %d succesfully follow id %d", followRequest.UserIdFollowing, followRequest.UserIdFollower)
 		message = "successfully followed"
+
+		// Gagal mengisi timeline tidak menggagalkan follow, feed akan fallback ke database
+		err = u.timelineUsecase.AddFollowing(ctx, followRequest.UserIdFollower, followRequest.UserIdFollowing)
+		if err != nil {
+			log.Printf("[FollowUser, AddFollowing] with error detail %v", err.Error())
+		}
 	} else {
 		err := u.followRepository.Delete(ctx, follow)
 		if err != nil {
@@ -92,6 +124,11 @@ func (u *followUsecaseImpl) FollowUser(ctx context.Context, followRequest reques
 		}
 		log.Printf("id %d succesfully unfollow id %d", followRequest.UserIdFollowing, followRequest.UserIdFollower)
 		message = "successfully unfollowed"
+
+		err = u.timelineUsecase.RemoveFollowing(ctx, followRequest.UserIdFollower, followRequest.UserIdFollowing)
+		if err != nil {
+			log.Printf("[FollowUser, RemoveFollowing] with error detail %v", err.Error())
+		}
 	}
 
 	return message, nil
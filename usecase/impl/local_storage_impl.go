@@ -0,0 +1,235 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/google/uuid"
+)
+
+// localStorage menyimpan file di disk dan menyajikannya lewat route static gin,
+// dipakai untuk development dan test supaya upload tidak butuh koneksi keluar
+type localStorage struct {
+	rootDir string
+	baseURL string
+}
+
+func NewLocalStorage(rootDir, baseURL string) usecase.StorageProvider {
+	return &localStorage{rootDir: rootDir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Upload implements usecase.StorageProvider.
+func (u *localStorage) Upload(ctx context.Context, file io.Reader, pathDestination string) (uri string, err error) {
+	content, key, err := readObject(file, pathDestination)
+	if err != nil {
+		log.Printf("[Upload, readObject] with error detail %v", err.Error())
+		return "", err
+	}
+
+	filePath := filepath.Join(u.rootDir, filepath.FromSlash(key))
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		log.Printf("[Upload, MkdirAll] with error detail %v", err.Error())
+		return "", err
+	}
+
+	err = os.WriteFile(filePath, content, 0o644)
+	if err != nil {
+		log.Printf("[Upload, WriteFile] with error detail %v", err.Error())
+		return "", err
+	}
+
+	return u.baseURL + "/" + key, nil
+}
+
+// Remove implements usecase.StorageProvider.
+func (u *localStorage) Remove(ctx context.Context, urlString string) (err error) {
+	filePath, err := u.filePath(urlString)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return helpers.ErrFileNotFound
+		}
+		log.Printf("[Remove, Remove] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Stat implements usecase.StorageProvider.
+func (u *localStorage) Stat(ctx context.Context, urlString string) (*usecase.StorageObject, error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := filepath.Join(u.rootDir, filepath.FromSlash(key))
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, helpers.ErrFileNotFound
+		}
+		log.Printf("[Stat, Stat] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	return &usecase.StorageObject{
+		Key:         key,
+		URL:         urlString,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(filePath)),
+		UpdatedAt:   info.ModTime(),
+	}, nil
+}
+
+// Open implements usecase.StorageProvider.
+func (u *localStorage) Open(ctx context.Context, urlString string) (io.ReadCloser, error) {
+	filePath, err := u.filePath(urlString)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, helpers.ErrFileNotFound
+		}
+		log.Printf("[Open, Open] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// List implements usecase.StorageProvider.
+func (u *localStorage) List(ctx context.Context, fn func(objects []usecase.StorageObject) error) error {
+	objects := make([]usecase.StorageObject, 0, storageListPageSize)
+
+	root := filepath.Join(u.rootDir, storageRootFolder)
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(u.rootDir, filePath)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relativePath)
+		objects = append(objects, usecase.StorageObject{
+			Key:         key,
+			URL:         u.baseURL + "/" + key,
+			Size:        info.Size(),
+			ContentType: mime.TypeByExtension(filepath.Ext(filePath)),
+			UpdatedAt:   info.ModTime(),
+		})
+
+		if len(objects) == storageListPageSize {
+			if err := fn(objects); err != nil {
+				return err
+			}
+			objects = make([]usecase.StorageObject, 0, storageListPageSize)
+		}
+
+		return nil
+	})
+
+	// folder belum ada berarti belum pernah ada upload
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("[List, WalkDir] with error detail %v", err.Error())
+		return err
+	}
+
+	if len(objects) > 0 {
+		return fn(objects)
+	}
+
+	return nil
+}
+
+// SignedURL implements usecase.StorageProvider.
+// Route static tidak memeriksa akses, jadi url publik langsung dikembalikan
+func (u *localStorage) SignedURL(ctx context.Context, urlString string, expiry time.Duration) (string, error) {
+	if _, err := u.filePath(urlString); err != nil {
+		return "", err
+	}
+
+	return urlString, nil
+}
+
+// PresignUpload implements usecase.StorageProvider.
+// Driver local tidak punya endpoint upload sendiri, jadi file tetap harus lewat /files/upload
+func (u *localStorage) PresignUpload(ctx context.Context, pathDestination, contentType string, maxSize int64, expiry time.Duration) (*usecase.PresignedUpload, error) {
+	return nil, helpers.ErrDirectUploadNotSupported
+}
+
+// ObjectKey implements usecase.StorageProvider.
+// Key ditolak kalau keluar dari storageRootFolder
+func (u *localStorage) ObjectKey(urlString string) (string, error) {
+	if !strings.HasPrefix(urlString, u.baseURL+"/") {
+		return "", helpers.ErrFileNotFound
+	}
+
+	key := path.Clean("/" + strings.TrimPrefix(urlString, u.baseURL+"/"))
+	if !strings.HasPrefix(key, "/"+storageRootFolder+"/") {
+		return "", helpers.ErrFileNotFound
+	}
+
+	return strings.TrimPrefix(key, "/"), nil
+}
+
+// filePath mengubah url publik menjadi path di disk di dalam rootDir
+func (u *localStorage) filePath(urlString string) (string, error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(u.rootDir, filepath.FromSlash(key)), nil
+}
+
+// readObject membaca seluruh file untuk mendeteksi content type dan
+// membuat key <storageRootFolder>/<pathDestination>/<uuid>.<ext>
+func readObject(file io.Reader, pathDestination string) ([]byte, string, error) {
+	buffer := bytes.NewBuffer(nil)
+
+	_, err := io.Copy(buffer, file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := http.DetectContentType(buffer.Bytes())
+	key := getPublicId(pathDestination, uuid.NewString()) + helpers.FileExtension(contentType)
+
+	return buffer.Bytes(), key, nil
+}
@@ -0,0 +1,299 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ariwiraa/my-gram/config"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+const (
+	mediaReaperBatchSize = 100
+	mediaReaperTimeout   = 10 * time.Minute
+
+	// reaper hanya dijalankan satu instance dalam satu waktu, lock diperpanjang selama reaper berjalan
+	mediaReaperLockKey = "media_reaper:lock"
+	mediaReaperLockTTL = time.Minute
+)
+
+// mediaReaperMetrics bisa dibaca lewat /admin/metrics
+var mediaReaperMetrics = expvar.NewMap("media_reaper")
+
+type mediaReaperUsecaseImpl struct {
+	mediaRepository repository.MediaRepository
+	redisRepository repository.RedisRepository
+	storageProvider usecase.StorageProvider
+	tempDir         string
+	cfg             config.MediaReaperConfig
+}
+
+func NewMediaReaperUsecaseImpl(mediaRepository repository.MediaRepository, redisRepository repository.RedisRepository, storageProvider usecase.StorageProvider, tempDir string, cfg config.MediaReaperConfig) usecase.MediaReaperUsecase {
+	return &mediaReaperUsecaseImpl{
+		mediaRepository: mediaRepository,
+		redisRepository: redisRepository,
+		storageProvider: storageProvider,
+		tempDir:         tempDir,
+		cfg:             cfg,
+	}
+}
+
+// Run implements usecase.MediaReaperUsecase.
+func (u *mediaReaperUsecaseImpl) Run(ctx context.Context) {
+	if u.cfg.Interval <= 0 {
+		log.Println("[MediaReaper, Run] media reaper is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(u.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reapCtx, cancel := context.WithTimeout(ctx, mediaReaperTimeout)
+			_, err := u.Reap(reapCtx)
+			cancel()
+			if err != nil {
+				log.Printf("[MediaReaper, Run, Reap] with error detail %v", err.Error())
+			}
+		}
+	}
+}
+
+// Reap implements usecase.MediaReaperUsecase.
+func (u *mediaReaperUsecaseImpl) Reap(ctx context.Context) (*usecase.MediaReaperResult, error) {
+	result := new(usecase.MediaReaperResult)
+	before := time.Now().Add(-u.cfg.MaxAge)
+
+	mediaReaperMetrics.Add("runs", 1)
+
+	err := u.reapStorage(ctx, before, result)
+
+	// file sementara ada di disk server ini, jadi setiap instance membersihkan miliknya sendiri
+	// walaupun storage bermasalah atau reaper sedang dijalankan instance lain
+	u.reapTempFiles(result)
+
+	// dry run tidak menghapus apa pun, jadi tidak dihitung di metrics
+	if !u.cfg.DryRun {
+		mediaReaperMetrics.Add("unclaimed_media_removed", int64(result.UnclaimedMedia))
+		mediaReaperMetrics.Add("orphan_objects_removed", int64(result.OrphanObjects))
+		mediaReaperMetrics.Add("temp_files_removed", int64(result.TempFiles))
+		mediaReaperMetrics.Add("remove_failures", int64(result.Failed))
+	}
+
+	log.Printf("[MediaReaper, Reap] removed %d unclaimed media, %d orphan objects, %d temp files, %d failed (dry run %t)",
+		result.UnclaimedMedia, result.OrphanObjects, result.TempFiles, result.Failed, u.cfg.DryRun)
+
+	if err != nil {
+		mediaReaperMetrics.Add("failed_runs", 1)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// reapStorage membersihkan media dan file storage yang dipakai bersama semua instance,
+// jadi hanya dijalankan oleh instance yang memegang lock
+func (u *mediaReaperUsecaseImpl) reapStorage(ctx context.Context, before time.Time, result *usecase.MediaReaperResult) error {
+	lock, locked, err := acquireLock(ctx, u.redisRepository, mediaReaperLockKey, mediaReaperLockTTL)
+	if err != nil {
+		log.Printf("[MediaReaper, reapStorage, acquireLock] with error detail %v", err.Error())
+		return err
+	}
+	if !locked {
+		log.Println("[MediaReaper, reapStorage] media reaper is running on another instance")
+		return nil
+	}
+	defer lock.release()
+
+	ctx, stopKeepAlive := lock.keepAlive(ctx)
+	defer stopKeepAlive()
+
+	err = u.reapUnclaimedMedia(ctx, before, result)
+	if err != nil {
+		return err
+	}
+
+	return u.reapOrphanObjects(ctx, before, result)
+}
+
+// reapUnclaimedMedia menghapus media yang tidak di-claim sampai before. Baris dihapus lebih dulu
+// supaya media tidak bisa di-claim setelah filenya hilang, file yang gagal dihapus
+// akan ditemukan lagi sebagai orphan object
+// Dry run tidak menghapus baris, jadi hanya batch pertama yang dilaporkan
+func (u *mediaReaperUsecaseImpl) reapUnclaimedMedia(ctx context.Context, before time.Time, result *usecase.MediaReaperResult) error {
+	for {
+		batch, err := u.mediaRepository.FindUnclaimedBefore(ctx, before, mediaReaperBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if u.cfg.DryRun {
+			for _, media := range batch {
+				log.Printf("[MediaReaper, reapUnclaimedMedia] dry run, would remove media %s (%s)", media.ID, media.PhotoUrl)
+			}
+			result.UnclaimedMedia += len(batch)
+			return nil
+		}
+
+		deleted := 0
+		for _, media := range batch {
+			err = u.mediaRepository.DeleteUnclaimed(ctx, media.ID)
+			if errors.Is(err, helpers.ErrMediaAlreadyClaimed) {
+				continue
+			}
+			if err != nil {
+				result.Failed++
+				continue
+			}
+			deleted++
+
+			for _, url := range []string{media.PhotoUrl, media.PosterUrl, media.MediumUrl, media.ThumbnailUrl} {
+				if url != "" && !u.remove(ctx, url) {
+					result.Failed++
+				}
+			}
+			result.UnclaimedMedia++
+		}
+
+		// berhenti kalau batch terakhir atau tidak ada yang bisa dihapus, supaya tidak berputar di baris yang sama
+		if len(batch) < mediaReaperBatchSize || deleted == 0 {
+			return nil
+		}
+	}
+}
+
+// reapOrphanObjects menghapus file di storage yang tidak dipakai media, foto maupun slide,
+// misalnya sisa hapus foto yang gagal atau presigned upload yang tidak pernah dikonfirmasi.
+// Storage dibaca per halaman dan setiap halaman dicocokkan ke database, jadi tidak ada daftar semua url di memory
+func (u *mediaReaperUsecaseImpl) reapOrphanObjects(ctx context.Context, before time.Time, result *usecase.MediaReaperResult) error {
+	return u.storageProvider.List(ctx, func(objects []usecase.StorageObject) error {
+		var candidates []usecase.StorageObject
+		for _, object := range objects {
+			if object.UpdatedAt.Before(before) {
+				candidates = append(candidates, object)
+			}
+		}
+
+		orphans, err := u.findOrphanObjects(ctx, candidates)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range orphans {
+			if u.cfg.DryRun {
+				log.Printf("[MediaReaper, reapOrphanObjects] dry run, would remove %s", object.URL)
+				result.OrphanObjects++
+				continue
+			}
+
+			if u.remove(ctx, object.URL) {
+				result.OrphanObjects++
+			} else {
+				result.Failed++
+			}
+		}
+
+		return nil
+	})
+}
+
+// findOrphanObjects mencocokkan url hasil listing ke database lebih dulu. File yang tidak ditemukan
+// dicek lagi lewat path di storage tanpa ekstensi, karena url yang sama bisa ditulis berbeda,
+// misalnya versi dan ekstensi di url cloudinary. Halaman storage dibaca sebelum database,
+// jadi file yang baru dipakai setelah listing tetap terlihat terpakai
+func (u *mediaReaperUsecaseImpl) findOrphanObjects(ctx context.Context, objects []usecase.StorageObject) ([]usecase.StorageObject, error) {
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	urls := make([]string, 0, len(objects))
+	for _, object := range objects {
+		urls = append(urls, object.URL)
+	}
+
+	referencedUrls, err := u.mediaRepository.FindReferencedUrls(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool, len(referencedUrls))
+	for _, url := range referencedUrls {
+		referenced[url] = true
+	}
+
+	var orphans []usecase.StorageObject
+	for _, object := range objects {
+		if referenced[object.URL] {
+			continue
+		}
+
+		// url yang tidak menunjuk ke storageRootFolder bukan file milik storage ini
+		key, err := cloudinaryPublicId(object.URL)
+		if err != nil {
+			continue
+		}
+
+		used, err := u.mediaRepository.IsKeyReferenced(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !used {
+			orphans = append(orphans, object)
+		}
+	}
+
+	return orphans, nil
+}
+
+// reapTempFiles menghapus potongan resumable upload yang tidak ditulis lagi sejak session-nya kedaluwarsa
+func (u *mediaReaperUsecaseImpl) reapTempFiles(result *usecase.MediaReaperResult) {
+	paths, err := filepath.Glob(filepath.Join(u.tempDir, "*.part"))
+	if err != nil {
+		log.Printf("[MediaReaper, reapTempFiles, Glob] with error detail %v", err.Error())
+		return
+	}
+
+	before := time.Now().Add(-uploadSessionTTL)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+
+		if u.cfg.DryRun {
+			log.Printf("[MediaReaper, reapTempFiles] dry run, would remove %s", path)
+			result.TempFiles++
+			continue
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			log.Printf("[MediaReaper, reapTempFiles, Remove] with error detail %v", err.Error())
+			result.Failed++
+			continue
+		}
+		result.TempFiles++
+	}
+}
+
+// remove menganggap file yang sudah tidak ada sebagai berhasil dihapus
+func (u *mediaReaperUsecaseImpl) remove(ctx context.Context, url string) bool {
+	err := u.storageProvider.Remove(ctx, url)
+	if err != nil && !errors.Is(err, helpers.ErrFileNotFound) {
+		log.Printf("[MediaReaper, remove, Remove] with error detail %v", err.Error())
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+// mentionStreamMaxLength membatasi panjang stream kalau consumer notifikasi tertinggal
+const mentionStreamMaxLength = 100_000
+
+type mentionUsecaseImpl struct {
+	userRepository  repository.UserRepository
+	redisRepository repository.RedisRepository
+}
+
+func NewMentionUsecaseImpl(userRepository repository.UserRepository, redisRepository repository.RedisRepository) usecase.MentionUsecase {
+	return &mentionUsecaseImpl{
+		userRepository:  userRepository,
+		redisRepository: redisRepository,
+	}
+}
+
+// Resolve implements usecase.MentionUsecase.
+func (u *mentionUsecaseImpl) Resolve(ctx context.Context, text string) ([]domain.Mention, error) {
+	tokens := helpers.ParseMentions(text)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	// satu username bisa muncul beberapa kali, semua username dicari dengan satu query
+	usernames := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		usernames = append(usernames, token.Username)
+	}
+
+	users, err := u.userRepository.FindIdsByUsernames(ctx, usernames)
+	if err != nil {
+		log.Printf("[Resolve, FindIdsByUsernames] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	userIds := make(map[string]uint, len(users))
+	for _, user := range users {
+		userIds[user.Username] = user.ID
+	}
+
+	var mentions []domain.Mention
+	for _, token := range tokens {
+		// username yang tidak terdaftar dibiarkan sebagai text biasa
+		userId, ok := userIds[token.Username]
+		if !ok {
+			continue
+		}
+
+		mentions = append(mentions, domain.Mention{
+			UserId:      userId,
+			Offset:      token.Offset,
+			Length:      token.Length,
+			OffsetUtf16: true,
+		})
+	}
+
+	return mentions, nil
+}
+
+// Publish implements usecase.MentionUsecase.
+func (u *mentionUsecaseImpl) Publish(ctx context.Context, event domain.MentionEvent, mentions []domain.Mention, previous []domain.Mention) error {
+	notified := map[uint]bool{event.AuthorId: true}
+	for _, mention := range previous {
+		notified[mention.UserId] = true
+	}
+
+	event.Type = domain.EventTypeMention
+	for _, mention := range mentions {
+		if notified[mention.UserId] {
+			continue
+		}
+		notified[mention.UserId] = true
+
+		event.MentionedUserId = mention.UserId
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		err = u.redisRepository.XAdd(ctx, usecase.MentionStreamKey, map[string]interface{}{"payload": string(payload)}, mentionStreamMaxLength)
+		if err != nil {
+			log.Printf("[Publish, XAdd] with error detail %v", err.Error())
+			return helpers.ErrRepository
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/google/uuid"
+)
+
+// photoMediaRequests mengembalikan slide dari request beserta id media-nya,
+// request yang hanya mengirim media id dianggap satu slide
+func photoMediaRequests(payload request.PhotoRequest) ([]request.PhotoMediaRequest, []string, error) {
+	items := payload.Media
+	if len(items) == 0 && payload.MediaId != "" {
+		items = []request.PhotoMediaRequest{{MediaId: payload.MediaId}}
+	}
+
+	if len(items) == 0 {
+		return nil, nil, helpers.ErrMediaRequired
+	}
+
+	if len(items) > domain.MaxPhotoMedia {
+		return nil, nil, helpers.ErrTooManyMedia
+	}
+
+	mediaIds := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.MediaId == "" {
+			return nil, nil, helpers.ErrMediaRequired
+		}
+
+		// media yang sama tidak boleh dipakai dua kali dalam satu postingan
+		if seen[item.MediaId] {
+			return nil, nil, helpers.ErrMediaAlreadyClaimed
+		}
+		seen[item.MediaId] = true
+
+		mediaIds = append(mediaIds, item.MediaId)
+	}
+
+	return items, mediaIds, nil
+}
+
+// buildPhotoMedia menyusun slide dari media hasil upload. Media milik user lain dianggap tidak ada
+func buildPhotoMedia(photoId string, userId uint, items []request.PhotoMediaRequest, uploads []domain.Media) ([]domain.PhotoMedia, error) {
+	uploadById := make(map[string]domain.Media, len(uploads))
+	for _, upload := range uploads {
+		uploadById[upload.ID] = upload
+	}
+
+	media := make([]domain.PhotoMedia, 0, len(items))
+	for position, item := range items {
+		upload, ok := uploadById[item.MediaId]
+		if !ok || upload.UserId != userId {
+			return nil, helpers.ErrMediaNotFound
+		}
+
+		if upload.Claimed {
+			return nil, helpers.ErrMediaAlreadyClaimed
+		}
+
+		media = append(media, domain.PhotoMedia{
+			ID:            uuid.NewString(),
+			PhotoId:       photoId,
+			Position:      position,
+			MediaType:     upload.MediaType,
+			PhotoUrl:      upload.PhotoUrl,
+			PosterUrl:     upload.PosterUrl,
+			MediumUrl:     upload.MediumUrl,
+			ThumbnailUrl:  upload.ThumbnailUrl,
+			Width:         upload.Width,
+			Height:        upload.Height,
+			BlurHash:      upload.BlurHash,
+			DominantColor: upload.DominantColor,
+			Duration:      upload.Duration,
+			AltText:       item.AltText,
+
+			PerceptualHash: upload.PerceptualHash,
+		})
+	}
+
+	return media, nil
+}
+
+// photoMediaOf mengembalikan slide sebuah foto. Foto yang dibuat sebelum ada carousel
+// belum punya baris media, jadi url di foto itu sendiri dijadikan satu slide dengan id foto
+func photoMediaOf(photo domain.Photo) []domain.PhotoMedia {
+	if len(photo.Media) > 0 || photo.PhotoUrl == "" {
+		return photo.Media
+	}
+
+	return []domain.PhotoMedia{{
+		ID:            photo.ID,
+		PhotoId:       photo.ID,
+		MediaType:     photo.MediaType,
+		PhotoUrl:      photo.PhotoUrl,
+		PosterUrl:     photo.PosterUrl,
+		MediumUrl:     photo.MediumUrl,
+		ThumbnailUrl:  photo.ThumbnailUrl,
+		Width:         photo.Width,
+		Height:        photo.Height,
+		BlurHash:      photo.BlurHash,
+		DominantColor: photo.DominantColor,
+		Duration:      photo.Duration,
+	}}
+}
+
+// reorderPhotoMedia mengurutkan slide sesuai order, slide yang tidak disebut masuk ke removed
+func reorderPhotoMedia(media []domain.PhotoMedia, order []string) (kept []domain.PhotoMedia, removed []domain.PhotoMedia, err error) {
+	mediaById := make(map[string]domain.PhotoMedia, len(media))
+	for _, item := range media {
+		mediaById[item.ID] = item
+	}
+
+	for position, id := range order {
+		item, ok := mediaById[id]
+		if !ok {
+			return nil, nil, helpers.ErrMediaOrderInvalid
+		}
+		delete(mediaById, id)
+
+		item.Position = position
+		kept = append(kept, item)
+	}
+
+	if len(kept) == 0 {
+		return nil, nil, helpers.ErrMediaRequired
+	}
+
+	for _, item := range media {
+		if _, ok := mediaById[item.ID]; ok {
+			removed = append(removed, item)
+		}
+	}
+
+	return kept, removed, nil
+}
+
+// setPhotoCover menyalin slide pertama ke foto supaya feed dan client lama tetap punya satu gambar
+func setPhotoCover(photo *domain.Photo, cover domain.PhotoMedia) {
+	photo.MediaType = cover.MediaType
+	photo.PhotoUrl = cover.PhotoUrl
+	photo.PosterUrl = cover.PosterUrl
+	photo.MediumUrl = cover.MediumUrl
+	photo.ThumbnailUrl = cover.ThumbnailUrl
+	photo.Width = cover.Width
+	photo.Height = cover.Height
+	photo.BlurHash = cover.BlurHash
+	photo.DominantColor = cover.DominantColor
+	photo.Duration = cover.Duration
+}
+
+// mediaUrls mengembalikan semua url rendition milik satu slide
+func mediaUrls(media domain.PhotoMedia) []string {
+	urls := make([]string, 0, 4)
+	for _, url := range []string{media.PhotoUrl, media.PosterUrl, media.MediumUrl, media.ThumbnailUrl} {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+func toPhotoMediaResponses(media []domain.PhotoMedia) []response.PhotoMediaResponse {
+	responses := make([]response.PhotoMediaResponse, 0, len(media))
+	for _, item := range media {
+		responses = append(responses, response.PhotoMediaResponse{
+			Id:            item.ID,
+			MediaType:     item.MediaType,
+			PhotoUrl:      item.PhotoUrl,
+			PosterUrl:     item.PosterUrl,
+			MediumUrl:     item.MediumUrl,
+			ThumbnailUrl:  item.ThumbnailUrl,
+			Width:         item.Width,
+			Height:        item.Height,
+			BlurHash:      item.BlurHash,
+			DominantColor: item.DominantColor,
+			Duration:      item.Duration,
+			AltText:       item.AltText,
+		})
+	}
+
+	return responses
+}
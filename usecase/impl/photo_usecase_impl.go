@@ -3,42 +3,62 @@ package impl
 import (
 	"context"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
 	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
 	"github.com/ariwiraa/my-gram/repository"
 	"github.com/ariwiraa/my-gram/usecase"
 	"github.com/google/uuid"
 )
 
+// similarPhotoLimit membatasi jumlah foto mirip yang dikembalikan ke moderator
+const similarPhotoLimit = 20
+
 type photoUsecase struct {
 	photoRepository          repository.PhotoRepository
 	commentRepository        repository.CommentRepository
 	tagRepository            repository.TagRepository
 	photoTagsRepository      repository.PhotoTagsRepository
+	photoMediaRepository     repository.PhotoMediaRepository
+	mediaRepository          repository.MediaRepository
 	userLikesPhotoRepository repository.UserLikesPhotoRepository
 	userRepository           repository.UserRepository
-	cloudinary               usecase.CloudinaryUsecase
+	mentionRepository        repository.MentionRepository
+	storageProvider          usecase.StorageProvider
+	timelineUsecase          usecase.TimelineUsecase
+	mentionUsecase           usecase.MentionUsecase
 }
 
 func NewPhotoUsecase(photo repository.PhotoRepository,
 	comment repository.CommentRepository,
 	tag repository.TagRepository,
 	photoTags repository.PhotoTagsRepository,
+	photoMedia repository.PhotoMediaRepository,
+	media repository.MediaRepository,
 	userLikesPhotoRepository repository.UserLikesPhotoRepository,
 	userRepository repository.UserRepository,
-	cloudinary usecase.CloudinaryUsecase,
+	mentionRepository repository.MentionRepository,
+	storageProvider usecase.StorageProvider,
+	timelineUsecase usecase.TimelineUsecase,
+	mentionUsecase usecase.MentionUsecase,
 ) usecase.PhotoUsecase {
 	return &photoUsecase{
 		photoRepository:          photo,
 		commentRepository:        comment,
 		tagRepository:            tag,
 		photoTagsRepository:      photoTags,
+		photoMediaRepository:     photoMedia,
+		mediaRepository:          media,
 		userLikesPhotoRepository: userLikesPhotoRepository,
 		userRepository:           userRepository,
-		cloudinary:               cloudinary,
+		mentionRepository:        mentionRepository,
+		storageProvider:          storageProvider,
+		timelineUsecase:          timelineUsecase,
+		mentionUsecase:           mentionUsecase,
 	}
 }
 
@@ -48,30 +68,81 @@ func (u *photoUsecase) Create(ctx context.Context, payload request.PhotoRequest,
 	defer cancel()
 
 	photo := domain.Photo{
-		ID:       uuid.NewString(),
-		Caption:  payload.Caption,
-		PhotoUrl: payload.PhotoUrl,
-		UserId:   userId,
+		ID:      uuid.NewString(),
+		Caption: payload.Caption,
+		UserId:  userId,
+	}
+
+	items, mediaIds, err := photoMediaRequests(payload)
+	if err != nil {
+		return &response.PhotoResponse{}, err
+	}
+
+	tagNames, err := photoTagNames(payload.Tags, payload.Caption)
+	if err != nil {
+		return &response.PhotoResponse{}, err
+	}
+
+	// mention ikut tersimpan bersama foto lewat asosiasi Mentions
+	photo.Mentions, err = u.mentionUsecase.Resolve(ctx, payload.Caption)
+	if err != nil {
+		log.Printf("[Create, Resolve] with error detail %v", err.Error())
+		return &response.PhotoResponse{}, err
+	}
+
+	uploads, err := u.mediaRepository.FindByIds(ctx, mediaIds)
+	if err != nil {
+		log.Printf("[Create, FindByIds] with error detail %v", err.Error())
+		return &response.PhotoResponse{}, err
+	}
+
+	media, err := buildPhotoMedia(photo.ID, userId, items, uploads)
+	if err != nil {
+		return &response.PhotoResponse{}, err
 	}
 
+	// claim tetap dicek di database karena media bisa saja dipakai request lain sejak dibaca di atas
+	err = u.mediaRepository.Claim(ctx, userId, mediaIds)
+	if err != nil {
+		return &response.PhotoResponse{}, err
+	}
+
+	// slide ikut tersimpan bersama foto lewat asosiasi Media
+	photo.Media = media
+	setPhotoCover(&photo, media[0])
+
 	usernameCh := make(chan string)
 	go u.fetchUsername(ctx, userId, usernameCh)
 
 	newPhoto, err := u.photoRepository.Create(ctx, photo)
 	if err != nil {
 		log.Printf("[Create, Create] with error detail %v", err.Error())
+		releaseErr := u.mediaRepository.Release(ctx, mediaIds)
+		if releaseErr != nil {
+			log.Printf("[Create, Release] with error detail %v", releaseErr.Error())
+		}
 		return &response.PhotoResponse{}, err
 	}
 
 	responsePhoto := response.PhotoResponse{
-		Id:        newPhoto.ID,
-		Caption:   newPhoto.Caption,
-		PhotoUrl:  newPhoto.PhotoUrl,
-		CreatedAt: newPhoto.CreatedAt,
-		Username:  <-usernameCh,
+		Id:            newPhoto.ID,
+		Caption:       newPhoto.Caption,
+		MediaType:     newPhoto.MediaType,
+		PhotoUrl:      newPhoto.PhotoUrl,
+		PosterUrl:     newPhoto.PosterUrl,
+		MediumUrl:     newPhoto.MediumUrl,
+		ThumbnailUrl:  newPhoto.ThumbnailUrl,
+		Width:         newPhoto.Width,
+		Height:        newPhoto.Height,
+		BlurHash:      newPhoto.BlurHash,
+		DominantColor: newPhoto.DominantColor,
+		Media:         toPhotoMediaResponses(newPhoto.Media),
+		Mentions:      newPhoto.Mentions,
+		CreatedAt:     newPhoto.CreatedAt,
+		Username:      <-usernameCh,
 	}
 
-	for _, photoTag := range payload.Tags {
+	for _, photoTag := range tagNames {
 		tag := domain.Tag{Name: photoTag}
 
 		newTag, err := u.tagRepository.AddTagIfNotExists(ctx, tag.Name)
@@ -95,12 +166,25 @@ func (u *photoUsecase) Create(ctx context.Context, payload request.PhotoRequest,
 		log.Println("Tag added succesfully")
 	}
 
+	// Gagal fan-out tidak menggagalkan upload, feed akan fallback ke database
+	err = u.timelineUsecase.FanOutPhoto(ctx, newPhoto)
+	if err != nil {
+		log.Printf("[Create, FanOutPhoto] with error detail %v", err.Error())
+	}
+
+	// notifikasi mention juga tidak menggagalkan upload
+	event := domain.MentionEvent{AuthorId: userId, PhotoId: newPhoto.ID, CreatedAt: time.Now()}
+	err = u.mentionUsecase.Publish(ctx, event, newPhoto.Mentions, nil)
+	if err != nil {
+		log.Printf("[Create, Publish] with error detail %v", err.Error())
+	}
+
 	log.Println("photo create succesfully")
 	return &responsePhoto, nil
 }
 
 // Delete implements PhotoUsecase
-func (u *photoUsecase) Delete(ctx context.Context, id string) error {
+func (u *photoUsecase) Delete(ctx context.Context, id string, actor domain.Actor) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -110,36 +194,48 @@ func (u *photoUsecase) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	err = u.cloudinary.Remove(ctx, photo.PhotoUrl, photo.User.ID)
+	err = authorizePhotoDelete(photo, actor)
 	if err != nil {
-		log.Printf("[Delete, Remove] with error detail %v", err.Error())
+		log.Printf("[Delete, authorizePhotoDelete] user %d cannot delete photo %s", actor.Id, id)
 		return err
 	}
 
-	err = u.photoTagsRepository.Delete(ctx, photo.ID)
+	err = u.photoRepository.Delete(ctx, photo)
 	if err != nil {
 		log.Printf("[Delete, Delete] with error detail %v", err.Error())
 		return err
 	}
 
-	err = u.photoRepository.Delete(ctx, photo)
+	// file dihapus setelah baris foto hilang, file yang gagal dihapus akan dibersihkan media reaper
+	for _, media := range photoMediaOf(photo) {
+		for _, url := range mediaUrls(media) {
+			err = u.storageProvider.Remove(ctx, url)
+			if err != nil && err != helpers.ErrFileNotFound {
+				log.Printf("[Delete, Remove] with error detail %v", err.Error())
+			}
+		}
+	}
+
+	err = u.timelineUsecase.RemovePhoto(ctx, photo)
 	if err != nil {
-		return err
+		log.Printf("[Delete, RemovePhoto] with error detail %v", err.Error())
 	}
 
 	return nil
 }
 
 // GetAll implements PhotoUsecase
-func (u *photoUsecase) GetAll(ctx context.Context) ([]domain.Photo, error) {
+func (u *photoUsecase) GetAll(ctx context.Context, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	photos, err := u.photoRepository.FindAll(ctx)
+	photos, err := u.photoRepository.FindAll(ctx, page)
 	if err != nil {
-		return photos, err
+		return photos, helpers.PageInfo{}, err
 	}
 
+	photos, pageInfo := helpers.Paginate(photos, page.Limit, photoCursor)
+
 	for _, photo := range photos {
 		totalComments, _ := u.commentRepository.CountCommentsByPhotoId(ctx, photo.ID)
 		photo.TotalComment = totalComments
@@ -147,20 +243,20 @@ func (u *photoUsecase) GetAll(ctx context.Context) ([]domain.Photo, error) {
 		photoTags, err := u.photoTagsRepository.FindPhotoTagsByPhotoId(ctx, photo.ID)
 		// Jika tidak ada tag, maka return photo
 		if err != nil {
-			return photos, nil
+			return photos, pageInfo, nil
 		}
 
 		for _, photoTag := range photoTags {
 			tag, err := u.tagRepository.FindById(ctx, photoTag.TagId)
 			if err != nil {
-				return photos, err
+				return photos, pageInfo, err
 			}
 
 			photo.Tags = append(photo.Tags, *tag)
 		}
 	}
 
-	return photos, nil
+	return photos, pageInfo, nil
 }
 
 func (u *photoUsecase) GetById(ctx context.Context, id string) (*response.PhotoResponse, error) {
@@ -191,7 +287,17 @@ func (u *photoUsecase) GetById(ctx context.Context, id string) (*response.PhotoR
 
 	responsePhoto := response.PhotoResponse{
 		Id:            photo.ID,
+		MediaType:     photo.MediaType,
 		PhotoUrl:      photo.PhotoUrl,
+		PosterUrl:     photo.PosterUrl,
+		MediumUrl:     photo.MediumUrl,
+		ThumbnailUrl:  photo.ThumbnailUrl,
+		Width:         photo.Width,
+		Height:        photo.Height,
+		BlurHash:      photo.BlurHash,
+		DominantColor: photo.DominantColor,
+		Media:         toPhotoMediaResponses(photoMediaOf(photo)),
+		Mentions:      photo.Mentions,
 		Caption:       photo.Caption,
 		CreatedAt:     photo.CreatedAt,
 		Username:      photo.User.Username,
@@ -209,25 +315,93 @@ func (u *photoUsecase) Update(ctx context.Context, payload request.UpdatePhotoRe
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	photo, err := u.photoRepository.FindByIdAndByUserId(ctx, id, userId)
+	photo, err := u.photoRepository.FindById(ctx, id)
+	if err != nil {
+		log.Printf("[Update, FindById] with error detail %v", err.Error())
+		return &response.PhotoResponse{}, err
+	}
+
+	err = authorizePhotoOwner(photo, userId)
 	if err != nil {
-		log.Printf("[Update, FIndByIdAndByUserId] with error detail %v", err.Error())
+		log.Printf("[Update, authorizePhotoOwner] user %d is not the owner of photo %s", userId, id)
 		return &response.PhotoResponse{}, err
 	}
 
 	photo.Caption = payload.Caption
+	previousMentions := photo.Mentions
+
+	mentions, err := u.mentionUsecase.Resolve(ctx, payload.Caption)
+	if err != nil {
+		log.Printf("[Update, Resolve] with error detail %v", err.Error())
+		return &response.PhotoResponse{}, err
+	}
+
+	var removedMedia []domain.PhotoMedia
+	if len(payload.Media) > 0 {
+		var keptMedia []domain.PhotoMedia
+		keptMedia, removedMedia, err = reorderPhotoMedia(photoMediaOf(photo), payload.Media)
+		if err != nil {
+			return &response.PhotoResponse{}, err
+		}
 
-	err = u.photoTagsRepository.Delete(ctx, id)
+		// foto lama tanpa baris media hanya punya satu slide, jadi tidak ada yang perlu disimpan
+		if len(photo.Media) > 0 {
+			photo.Media = keptMedia
+		}
+
+		setPhotoCover(&photo, keptMedia[0])
+	}
+
+	tagNames, err := photoTagNames(payload.Tags, payload.Caption)
 	if err != nil {
-		log.Printf("[Update, Delete] with error detail %v", err.Error())
 		return &response.PhotoResponse{}, err
 	}
 
-	updatedPhoto, err := u.photoRepository.Update(ctx, *photo, id)
+	// tag dibuat lebih dulu, relasinya baru diganti bersama foto dalam satu transaksi
+	var tags []domain.Tag
+	for _, photoTag := range tagNames {
+		newTag, err := u.tagRepository.AddTagIfNotExists(ctx, photoTag)
+		if err != nil {
+			log.Printf("[Update, AddTagIfNotExists] with error detail %v", err.Error())
+			return &response.PhotoResponse{}, err
+		}
+		tags = append(tags, *newTag)
+	}
+
+	tagIds := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		tagIds = append(tagIds, tag.ID)
+	}
+
+	updatedPhoto, err := u.photoRepository.Update(ctx, photo, id, tagIds)
 	if err != nil {
 		log.Printf("[Update, Update] with error detail %v", err.Error())
 		return &response.PhotoResponse{}, err
 	}
+	updatedPhoto.Tags = tags
+
+	updatedPhoto.Mentions, err = u.mentionRepository.ReplaceByPhotoId(ctx, id, mentions)
+	if err != nil {
+		log.Printf("[Update, ReplaceByPhotoId] with error detail %v", err.Error())
+		return &response.PhotoResponse{}, err
+	}
+
+	// hanya user yang baru di-mention yang dinotifikasi
+	event := domain.MentionEvent{AuthorId: userId, PhotoId: id, CreatedAt: time.Now()}
+	err = u.mentionUsecase.Publish(ctx, event, updatedPhoto.Mentions, previousMentions)
+	if err != nil {
+		log.Printf("[Update, Publish] with error detail %v", err.Error())
+	}
+
+	// file slide yang dihapus dibersihkan setelah urutan baru tersimpan
+	for _, media := range removedMedia {
+		for _, url := range mediaUrls(media) {
+			err = u.storageProvider.Remove(ctx, url)
+			if err != nil && err != helpers.ErrFileNotFound {
+				log.Printf("[Update, Remove] with error detail %v", err.Error())
+			}
+		}
+	}
 
 	totalCommentsCh := make(chan int64)
 	totalLikesCh := make(chan int64)
@@ -243,7 +417,17 @@ func (u *photoUsecase) Update(ctx context.Context, payload request.UpdatePhotoRe
 
 	responsePhoto := response.PhotoResponse{
 		Id:            updatedPhoto.ID,
+		MediaType:     updatedPhoto.MediaType,
 		PhotoUrl:      updatedPhoto.PhotoUrl,
+		PosterUrl:     updatedPhoto.PosterUrl,
+		MediumUrl:     updatedPhoto.MediumUrl,
+		ThumbnailUrl:  updatedPhoto.ThumbnailUrl,
+		Width:         updatedPhoto.Width,
+		Height:        updatedPhoto.Height,
+		BlurHash:      updatedPhoto.BlurHash,
+		DominantColor: updatedPhoto.DominantColor,
+		Media:         toPhotoMediaResponses(photoMediaOf(updatedPhoto)),
+		Mentions:      updatedPhoto.Mentions,
 		Caption:       updatedPhoto.Caption,
 		CreatedAt:     updatedPhoto.CreatedAt,
 		Username:      username,
@@ -251,42 +435,24 @@ func (u *photoUsecase) Update(ctx context.Context, payload request.UpdatePhotoRe
 		TotalLikes:    totalLikes,
 	}
 
-	for _, photoTag := range payload.Tags {
-		tag := domain.Tag{Name: photoTag}
-
-		newTag, err := u.tagRepository.AddTagIfNotExists(ctx, tag.Name)
-		if err != nil {
-			log.Printf("[Update, AddTagIfNotExists] with error detail %v", err.Error())
-			return &responsePhoto, err
-		}
-
-		photoTags := domain.PhotoTags{
-			PhotoId: updatedPhoto.ID,
-			TagId:   newTag.ID,
-		}
-
-		err = u.photoTagsRepository.Add(ctx, photoTags)
-		if err != nil {
-			log.Printf("[Update, Add] with error detail %v", err.Error())
-			return &responsePhoto, err
-		}
-		// Hubungkan tag dengan foto
-		updatedPhoto.Tags = append(updatedPhoto.Tags, *newTag)
-
+	for _, tag := range updatedPhoto.Tags {
+		responsePhoto.PhotoTags = append(responsePhoto.PhotoTags, tag.Name)
 	}
 
 	return &responsePhoto, nil
 }
 
-func (u *photoUsecase) GetAllPhotosByUserId(ctx context.Context, userId uint) ([]domain.Photo, error) {
+func (u *photoUsecase) GetAllPhotosByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	photos, err := u.photoRepository.FindByUserId(ctx, userId)
+	photos, err := u.photoRepository.FindByUserId(ctx, userId, page)
 	if err != nil {
-		return photos, err
+		return photos, helpers.PageInfo{}, err
 	}
 
+	photos, pageInfo := helpers.Paginate(photos, page.Limit, photoCursor)
+
 	for _, photo := range photos {
 		totalComments, _ := u.commentRepository.CountCommentsByPhotoId(ctx, photo.ID)
 		photo.TotalComment = totalComments
@@ -295,14 +461,14 @@ func (u *photoUsecase) GetAllPhotosByUserId(ctx context.Context, userId uint) ([
 		// Jika tidak ada tag, maka return photo
 		if err != nil {
 			log.Printf("[GetAllPhotosByUserId, FindPhotoTagsByPhotoId] with error detail %v", err.Error())
-			return photos, nil
+			return photos, pageInfo, nil
 		}
 
 		for _, photoTag := range photoTags {
 			tag, err := u.tagRepository.FindById(ctx, photoTag.TagId)
 			if err != nil {
 				log.Printf("[GetAllPhotosByUserId, FindById] with error detail %v", err.Error())
-				return photos, err
+				return photos, pageInfo, err
 			}
 
 			photo.Tags = append(photo.Tags, *tag)
@@ -310,7 +476,27 @@ func (u *photoUsecase) GetAllPhotosByUserId(ctx context.Context, userId uint) ([
 		}
 	}
 
-	return photos, nil
+	return photos, pageInfo, nil
+}
+
+// photoTagNames menggabungkan tag dari request dengan #hashtag di caption,
+// semuanya dinormalisasi supaya tag yang sama tidak tersimpan dua kali.
+// Tag dari request yang tidak valid ditolak, hashtag di caption yang tidak muat cukup diabaikan
+func photoTagNames(tags []string, caption string) ([]string, error) {
+	names, err := helpers.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hashtag := range helpers.ParseHashtags(caption) {
+		names = helpers.AppendTag(names, hashtag)
+	}
+
+	return names, nil
+}
+
+func photoCursor(photo domain.Photo) helpers.Cursor {
+	return helpers.Cursor{CreatedAt: *photo.CreatedAt, Id: photo.ID}
 }
 
 func (u *photoUsecase) calculateTotalComments(ctx context.Context, photoID string, resultCh chan<- int64) {
@@ -356,3 +542,105 @@ func (u *photoUsecase) processPhotoTags(ctx context.Context, photoTags []domain.
 		responsePhoto.PhotoTags = append(responsePhoto.PhotoTags, tag.Name)
 	}
 }
+
+// GetSimilar implements PhotoUsecase
+func (u *photoUsecase) GetSimilar(ctx context.Context, id string) ([]response.SimilarPhotoResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	photo, err := u.photoRepository.FindById(ctx, id)
+	if err != nil {
+		log.Printf("[GetSimilar, FindById] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	// satu foto bisa mirip dengan beberapa slide, yang dipakai jarak terkecil
+	distanceByPhotoId := make(map[string]int)
+	for _, media := range photo.Media {
+		if media.PerceptualHash == 0 {
+			continue
+		}
+
+		similar, err := u.photoMediaRepository.FindSimilar(ctx, media.PerceptualHash, helpers.PERCEPTUAL_HASH_MAX_DISTANCE, photo.ID, similarPhotoLimit)
+		if err != nil {
+			log.Printf("[GetSimilar, FindSimilar] with error detail %v", err.Error())
+			return nil, err
+		}
+
+		for _, item := range similar {
+			distance, ok := distanceByPhotoId[item.PhotoId]
+			if !ok || item.Distance < distance {
+				distanceByPhotoId[item.PhotoId] = item.Distance
+			}
+		}
+	}
+
+	similarPhotos := make([]response.SimilarPhotoResponse, 0, len(distanceByPhotoId))
+	if len(distanceByPhotoId) == 0 {
+		return similarPhotos, nil
+	}
+
+	photoIds := make([]string, 0, len(distanceByPhotoId))
+	for photoId := range distanceByPhotoId {
+		photoIds = append(photoIds, photoId)
+	}
+
+	photos, err := u.photoRepository.FindPhotosByIDList(ctx, photoIds)
+	if err != nil {
+		log.Printf("[GetSimilar, FindPhotosByIDList] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	totalComments, err := u.commentRepository.CountCommentsByPhotoIds(ctx, photoIds)
+	if err != nil {
+		log.Printf("[GetSimilar, CountCommentsByPhotoIds] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	totalLikes, err := u.userLikesPhotoRepository.CountLikesByPhotoIds(ctx, photoIds)
+	if err != nil {
+		log.Printf("[GetSimilar, CountLikesByPhotoIds] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	for _, similarPhoto := range photos {
+		responsePhoto := response.PhotoResponse{
+			Id:            similarPhoto.ID,
+			Caption:       similarPhoto.Caption,
+			MediaType:     similarPhoto.MediaType,
+			PhotoUrl:      similarPhoto.PhotoUrl,
+			PosterUrl:     similarPhoto.PosterUrl,
+			MediumUrl:     similarPhoto.MediumUrl,
+			ThumbnailUrl:  similarPhoto.ThumbnailUrl,
+			Width:         similarPhoto.Width,
+			Height:        similarPhoto.Height,
+			BlurHash:      similarPhoto.BlurHash,
+			DominantColor: similarPhoto.DominantColor,
+			Media:         toPhotoMediaResponses(photoMediaOf(similarPhoto)),
+			Mentions:      similarPhoto.Mentions,
+			TotalLikes:    totalLikes[similarPhoto.ID],
+			TotalComments: totalComments[similarPhoto.ID],
+			Username:      similarPhoto.User.Username,
+			CreatedAt:     similarPhoto.CreatedAt,
+		}
+
+		for _, tag := range similarPhoto.Tags {
+			responsePhoto.PhotoTags = append(responsePhoto.PhotoTags, tag.Name)
+		}
+
+		similarPhotos = append(similarPhotos, response.SimilarPhotoResponse{
+			PhotoResponse: responsePhoto,
+			Distance:      distanceByPhotoId[similarPhoto.ID],
+		})
+	}
+
+	sort.SliceStable(similarPhotos, func(i, j int) bool {
+		return similarPhotos[i].Distance < similarPhotos[j].Distance
+	})
+
+	if len(similarPhotos) > similarPhotoLimit {
+		similarPhotos = similarPhotos[:similarPhotoLimit]
+	}
+
+	return similarPhotos, nil
+}
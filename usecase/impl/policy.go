@@ -0,0 +1,56 @@
+package impl
+
+import (
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+)
+
+// authorizePhotoOwner memastikan hanya pemilik foto yang bisa mengubah atau menghapus foto
+func authorizePhotoOwner(photo domain.Photo, userId uint) error {
+	if photo.UserId != userId {
+		return helpers.ErrorForbiddenAccess
+	}
+
+	return nil
+}
+
+// authorizePhotoDelete mengizinkan pemilik foto, moderator dan admin untuk menghapus foto
+func authorizePhotoDelete(photo domain.Photo, actor domain.Actor) error {
+	if actor.IsStaff() {
+		return nil
+	}
+
+	return authorizePhotoOwner(photo, actor.Id)
+}
+
+// authorizeCommentAuthor memastikan hanya penulis comment yang bisa mengubah comment
+func authorizeCommentAuthor(comment domain.Comment, userId uint) error {
+	if comment.UserId != userId {
+		return helpers.ErrorForbiddenAccess
+	}
+
+	return nil
+}
+
+// authorizeCommentModerator mengizinkan penulis comment, pemilik foto, moderator dan admin untuk menghapus comment
+func authorizeCommentModerator(comment domain.Comment, photo domain.Photo, actor domain.Actor) error {
+	if actor.IsStaff() || comment.UserId == actor.Id || photo.UserId == actor.Id {
+		return nil
+	}
+
+	return helpers.ErrorForbiddenAccess
+}
+
+// authorizeUserModeration memastikan staff tidak mengubah akun sendiri
+// dan moderator hanya bisa menindak user biasa
+func authorizeUserModeration(target domain.User, actor domain.Actor) error {
+	if target.ID == actor.Id {
+		return helpers.ErrorForbiddenAccess
+	}
+
+	if actor.Role != domain.RoleAdmin && target.Role != domain.RoleUser {
+		return helpers.ErrorForbiddenAccess
+	}
+
+	return nil
+}
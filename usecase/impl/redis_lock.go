@@ -0,0 +1,71 @@
+package impl
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/google/uuid"
+)
+
+// redisLock adalah lock di redis yang isinya token acak milik pemegangnya,
+// jadi lock yang sudah kedaluwarsa dan diambil request lain tidak ikut terlepas
+type redisLock struct {
+	redisRepository repository.RedisRepository
+	key             string
+	token           string
+	ttl             time.Duration
+}
+
+// acquireLock mengembalikan false kalau lock sedang dipegang pihak lain
+func acquireLock(ctx context.Context, redisRepository repository.RedisRepository, key string, ttl time.Duration) (*redisLock, bool, error) {
+	lock := &redisLock{
+		redisRepository: redisRepository,
+		key:             key,
+		token:           uuid.NewString(),
+		ttl:             ttl,
+	}
+
+	locked, err := redisRepository.SetNX(ctx, key, lock.token, ttl)
+	if err != nil || !locked {
+		return nil, false, err
+	}
+
+	return lock, true, nil
+}
+
+// keepAlive memperpanjang ttl lock selama pekerjaan berjalan. Context yang dikembalikan dibatalkan
+// kalau lock tidak bisa diperpanjang, pekerjaan harus berhenti karena lock mungkin sudah dipegang pihak lain
+func (l *redisLock) keepAlive(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				held, err := l.redisRepository.ExpireIfValue(ctx, l.key, l.token, l.ttl)
+				if err != nil || !held {
+					log.Printf("[redisLock, keepAlive] lock %s lost: %v", l.key, err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
+func (l *redisLock) release() {
+	// context request bisa sudah selesai, lock tetap harus dilepas
+	_, err := l.redisRepository.DelIfValue(context.Background(), l.key, l.token)
+	if err != nil {
+		log.Printf("[redisLock, release] with error detail %v", err.Error())
+	}
+}
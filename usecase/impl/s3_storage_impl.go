@@ -0,0 +1,211 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+)
+
+// s3Storage menyimpan file di storage yang kompatibel dengan S3, misalnya MinIO
+type s3Storage struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+func NewS3Storage(client *minio.Client, bucket, baseURL string) usecase.StorageProvider {
+	return &s3Storage{client: client, bucket: bucket, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Upload implements usecase.StorageProvider.
+func (u *s3Storage) Upload(ctx context.Context, file io.Reader, pathDestination string) (uri string, err error) {
+	content, key, err := readObject(file, pathDestination)
+	if err != nil {
+		log.Printf("[Upload, readObject] with error detail %v", err.Error())
+		return "", err
+	}
+
+	_, err = u.client.PutObject(ctx, u.bucket, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: http.DetectContentType(content),
+	})
+	if err != nil {
+		log.Printf("[Upload, PutObject] with error detail %v", err.Error())
+		return "", err
+	}
+
+	return u.baseURL + "/" + key, nil
+}
+
+// Remove implements usecase.StorageProvider.
+func (u *s3Storage) Remove(ctx context.Context, urlString string) (err error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return err
+	}
+
+	// RemoveObject tidak mengembalikan error untuk key yang tidak ada, jadi dicek dulu
+	_, err = u.client.StatObject(ctx, u.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return s3Error(err)
+	}
+
+	err = u.client.RemoveObject(ctx, u.bucket, key, minio.RemoveObjectOptions{})
+	if err != nil {
+		log.Printf("[Remove, RemoveObject] with error detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Stat implements usecase.StorageProvider.
+func (u *s3Storage) Stat(ctx context.Context, urlString string) (*usecase.StorageObject, error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := u.client.StatObject(ctx, u.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	return &usecase.StorageObject{
+		Key:         info.Key,
+		URL:         urlString,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		UpdatedAt:   info.LastModified,
+	}, nil
+}
+
+// Open implements usecase.StorageProvider.
+func (u *s3Storage) Open(ctx context.Context, urlString string) (io.ReadCloser, error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := u.client.GetObject(ctx, u.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	// GetObject baru menghubungi storage saat dibaca, Stat dipakai supaya key yang tidak ada langsung ketahuan
+	_, err = object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, s3Error(err)
+	}
+
+	return object, nil
+}
+
+// List implements usecase.StorageProvider.
+func (u *s3Storage) List(ctx context.Context, fn func(objects []usecase.StorageObject) error) error {
+	// listing dihentikan kalau fn gagal, context ini menutup channel ListObjects
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objects := make([]usecase.StorageObject, 0, storageListPageSize)
+	for info := range u.client.ListObjects(ctx, u.bucket, minio.ListObjectsOptions{
+		Prefix:    storageRootFolder + "/",
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			log.Printf("[List, ListObjects] with error detail %v", info.Err.Error())
+			return info.Err
+		}
+
+		objects = append(objects, usecase.StorageObject{
+			Key:         info.Key,
+			URL:         u.baseURL + "/" + info.Key,
+			Size:        info.Size,
+			ContentType: info.ContentType,
+			UpdatedAt:   info.LastModified,
+		})
+
+		if len(objects) == storageListPageSize {
+			if err := fn(objects); err != nil {
+				return err
+			}
+			objects = make([]usecase.StorageObject, 0, storageListPageSize)
+		}
+	}
+
+	if len(objects) > 0 {
+		return fn(objects)
+	}
+
+	return nil
+}
+
+// SignedURL implements usecase.StorageProvider.
+func (u *s3Storage) SignedURL(ctx context.Context, urlString string, expiry time.Duration) (string, error) {
+	key, err := u.ObjectKey(urlString)
+	if err != nil {
+		return "", err
+	}
+
+	signedURL, err := u.client.PresignedGetObject(ctx, u.bucket, key, expiry, nil)
+	if err != nil {
+		log.Printf("[SignedURL, PresignedGetObject] with error detail %v", err.Error())
+		return "", err
+	}
+
+	return signedURL.String(), nil
+}
+
+// PresignUpload implements usecase.StorageProvider.
+// Policy mengunci key, content type dan ukuran maksimal, jadi client tidak bisa menulis file lain
+func (u *s3Storage) PresignUpload(ctx context.Context, pathDestination, contentType string, maxSize int64, expiry time.Duration) (*usecase.PresignedUpload, error) {
+	key := getPublicId(pathDestination, uuid.NewString()) + helpers.FileExtension(contentType)
+	expiresAt := time.Now().Add(expiry)
+
+	policy := minio.NewPostPolicy()
+	policy.SetBucket(u.bucket)
+	policy.SetKey(key)
+	policy.SetExpires(expiresAt)
+	policy.SetContentType(contentType)
+	policy.SetContentLengthRange(1, maxSize)
+
+	uploadURL, fields, err := u.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		log.Printf("[PresignUpload, PresignedPostPolicy] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	return &usecase.PresignedUpload{
+		UploadURL: uploadURL.String(),
+		Fields:    fields,
+		FileURL:   u.baseURL + "/" + key,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ObjectKey implements usecase.StorageProvider.
+func (u *s3Storage) ObjectKey(urlString string) (string, error) {
+	if !strings.HasPrefix(urlString, u.baseURL+"/"+storageRootFolder+"/") {
+		return "", helpers.ErrFileNotFound
+	}
+
+	return strings.TrimPrefix(urlString, u.baseURL+"/"), nil
+}
+
+func s3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return helpers.ErrFileNotFound
+	}
+
+	log.Printf("[s3Storage] with error detail %v", err.Error())
+	return err
+}
@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/ariwiraa/my-gram/config"
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+const (
+	tagSearchLimit = 10
+
+	trendingTagsKey   = "tags:trending"
+	trendingTagsLimit = 20
+	// tag dengan foto baru lebih sedikit dari ini dianggap belum trending
+	trendingMinRecentPhotos = 3
+)
+
+type tagUsecaseImpl struct {
+	tagRepository       repository.TagRepository
+	photoTagsRepository repository.PhotoTagsRepository
+	photoRepository     repository.PhotoRepository
+	commentRepository   repository.CommentRepository
+	redisRepository     repository.RedisRepository
+	cfg                 config.TrendingTagsConfig
+}
+
+func NewTagUsecaseImpl(tagRepository repository.TagRepository, photoTagsRepository repository.PhotoTagsRepository, photoRepository repository.PhotoRepository, commentRepository repository.CommentRepository, redisRepository repository.RedisRepository, cfg config.TrendingTagsConfig) usecase.TagUsecase {
+	return &tagUsecaseImpl{
+		tagRepository:       tagRepository,
+		photoTagsRepository: photoTagsRepository,
+		photoRepository:     photoRepository,
+		commentRepository:   commentRepository,
+		redisRepository:     redisRepository,
+		cfg:                 cfg,
+	}
+}
+
+// GetByName implements usecase.TagUsecase.
+func (u *tagUsecaseImpl) GetByName(ctx context.Context, name string) (*response.TagResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := u.findTag(ctx, name)
+	if err != nil {
+		log.Printf("[GetByName, findTag] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	totalPhotos, err := u.photoTagsRepository.CountByTagId(ctx, tag.ID)
+	if err != nil {
+		log.Printf("[GetByName, CountByTagId] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	return &response.TagResponse{
+		Id:          tag.ID,
+		Name:        tag.Name,
+		TotalPhotos: totalPhotos,
+	}, nil
+}
+
+// GetPhotosByName implements usecase.TagUsecase.
+func (u *tagUsecaseImpl) GetPhotosByName(ctx context.Context, name string, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tag, err := u.findTag(ctx, name)
+	if err != nil {
+		log.Printf("[GetPhotosByName, findTag] with error detail %v", err.Error())
+		return nil, helpers.PageInfo{}, err
+	}
+
+	photos, err := u.photoRepository.FindByTagId(ctx, tag.ID, page)
+	if err != nil {
+		log.Printf("[GetPhotosByName, FindByTagId] with error detail %v", err.Error())
+		return nil, helpers.PageInfo{}, err
+	}
+
+	photos, pageInfo := helpers.Paginate(photos, page.Limit, photoCursor)
+	if len(photos) == 0 {
+		return photos, pageInfo, nil
+	}
+
+	photoIds := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		photoIds = append(photoIds, photo.ID)
+	}
+
+	totalComments, err := u.commentRepository.CountCommentsByPhotoIds(ctx, photoIds)
+	if err != nil {
+		log.Printf("[GetPhotosByName, CountCommentsByPhotoIds] with error detail %v", err.Error())
+		return nil, helpers.PageInfo{}, err
+	}
+
+	for i := range photos {
+		photos[i].TotalComment = totalComments[photos[i].ID]
+	}
+
+	return photos, pageInfo, nil
+}
+
+// Search implements usecase.TagUsecase.
+func (u *tagUsecaseImpl) Search(ctx context.Context, query string) ([]response.TagResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	prefix := domain.NormalizeTag(query)
+	if prefix == "" {
+		return nil, helpers.ErrTagQueryRequired
+	}
+
+	tags, err := u.tagRepository.SearchByPrefix(ctx, prefix, tagSearchLimit)
+	if err != nil {
+		log.Printf("[Search, SearchByPrefix] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	responses := make([]response.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responses = append(responses, response.TagResponse{
+			Id:          tag.ID,
+			Name:        tag.Name,
+			TotalPhotos: tag.TotalPhotos,
+		})
+	}
+
+	return responses, nil
+}
+
+// GetTrending implements usecase.TagUsecase.
+func (u *tagUsecaseImpl) GetTrending(ctx context.Context) ([]domain.TrendingTag, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// redis hanya cache, kalau gagal dibaca tag trending dihitung langsung dari database
+	if u.cfg.CacheTTL > 0 {
+		cached, err := u.redisRepository.Get(ctx, trendingTagsKey)
+		if err == nil {
+			var tags []domain.TrendingTag
+			err = json.Unmarshal([]byte(cached.(string)), &tags)
+			if err == nil {
+				return tags, nil
+			}
+			log.Printf("[GetTrending, Unmarshal] with error detail %v", err.Error())
+		}
+	}
+
+	tags, err := u.tagRepository.FindTrending(ctx, time.Now(), u.cfg.Window, trendingMinRecentPhotos, trendingTagsLimit)
+	if err != nil {
+		log.Printf("[GetTrending, FindTrending] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	if tags == nil {
+		tags = []domain.TrendingTag{}
+	}
+
+	if u.cfg.CacheTTL > 0 {
+		value, err := json.Marshal(tags)
+		if err == nil {
+			err = u.redisRepository.Set(ctx, trendingTagsKey, string(value), u.cfg.CacheTTL)
+		}
+		if err != nil {
+			log.Printf("[GetTrending, Set] with error detail %v", err.Error())
+		}
+	}
+
+	return tags, nil
+}
+
+// findTag menormalisasi name dengan aturan yang sama seperti saat tag disimpan
+func (u *tagUsecaseImpl) findTag(ctx context.Context, name string) (*domain.Tag, error) {
+	normalizedName := domain.NormalizeTag(name)
+	if normalizedName == "" {
+		return nil, helpers.ErrTagNotFound
+	}
+
+	return u.tagRepository.FindOneByName(ctx, normalizedName)
+}
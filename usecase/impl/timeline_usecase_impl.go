@@ -0,0 +1,302 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ariwiraa/my-gram/config"
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+)
+
+type timelineUsecaseImpl struct {
+	redisRepository  repository.RedisRepository
+	followRepository repository.FollowRepository
+	photoRepository  repository.PhotoRepository
+	cfg              config.TimelineConfig
+}
+
+func NewTimelineUsecaseImpl(redisRepository repository.RedisRepository, followRepository repository.FollowRepository, photoRepository repository.PhotoRepository, cfg config.TimelineConfig) usecase.TimelineUsecase {
+	return &timelineUsecaseImpl{
+		redisRepository:  redisRepository,
+		followRepository: followRepository,
+		photoRepository:  photoRepository,
+		cfg:              cfg,
+	}
+}
+
+// FanOutPhoto implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) FanOutPhoto(ctx context.Context, photo domain.Photo) error {
+	followerKeys, err := u.followerTimelineKeys(ctx, photo.UserId)
+	if err != nil {
+		log.Printf("[FanOutPhoto, followerTimelineKeys] with error detail %v", err.Error())
+		return err
+	}
+
+	if len(followerKeys) == 0 {
+		return nil
+	}
+
+	member := repository.ZMember{
+		Member: photo.ID,
+		Score:  timelineScore(*photo.CreatedAt),
+	}
+
+	err = u.redisRepository.ZAddCapped(ctx, followerKeys, member, u.cfg.MaxLength)
+	if err != nil {
+		log.Printf("[FanOutPhoto, ZAddCapped] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	log.Printf("photo %s fanned out to %d timelines", photo.ID, len(followerKeys))
+	return nil
+}
+
+// RemovePhoto implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) RemovePhoto(ctx context.Context, photo domain.Photo) error {
+	followerKeys, err := u.followerTimelineKeys(ctx, photo.UserId)
+	if err != nil {
+		log.Printf("[RemovePhoto, followerTimelineKeys] with error detail %v", err.Error())
+		return err
+	}
+
+	if len(followerKeys) == 0 {
+		return nil
+	}
+
+	err = u.redisRepository.ZRem(ctx, followerKeys, photo.ID)
+	if err != nil {
+		log.Printf("[RemovePhoto, ZRem] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// GetPhotoIds implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) GetPhotoIds(ctx context.Context, userId uint, page helpers.PageRequest) ([]string, bool, error) {
+	err := u.backfill(ctx, userId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := timelineKey(userId)
+	count := int64(page.Limit + 1)
+
+	photoIds := make([]string, 0, count)
+	max := "+inf"
+	if page.Cursor != nil {
+		score := strconv.FormatFloat(timelineScore(page.Cursor.CreatedAt), 'f', -1, 64)
+
+		// foto dengan created_at yang sama dengan cursor diurutkan redis berdasarkan id secara descending,
+		// yang belum dikirim hanya yang id-nya lebih kecil dari id cursor
+		ties, err := u.redisRepository.ZRevRangeByScore(ctx, key, score, score, 0)
+		if err != nil {
+			log.Printf("[GetPhotoIds, ZRevRangeByScore] with error detail %v", err.Error())
+			return nil, false, helpers.ErrRepository
+		}
+
+		for _, member := range ties {
+			if member.Member < page.Cursor.Id && int64(len(photoIds)) < count {
+				photoIds = append(photoIds, member.Member)
+			}
+		}
+
+		max = "(" + score
+	}
+
+	if remaining := count - int64(len(photoIds)); remaining > 0 {
+		members, err := u.redisRepository.ZRevRangeByScore(ctx, key, "-inf", max, remaining)
+		if err != nil {
+			log.Printf("[GetPhotoIds, ZRevRangeByScore] with error detail %v", err.Error())
+			return nil, false, helpers.ErrRepository
+		}
+
+		for _, member := range members {
+			photoIds = append(photoIds, member.Member)
+		}
+	}
+
+	total, err := u.redisRepository.ZCard(ctx, key)
+	if err != nil {
+		log.Printf("[GetPhotoIds, ZCard] with error detail %v", err.Error())
+		return nil, false, helpers.ErrRepository
+	}
+
+	return photoIds, total >= u.cfg.MaxLength, nil
+}
+
+// backfill mengisi timeline dari database saat pertama kali dibaca. Tanpa ini user yang sudah follow
+// sebelum fan-out ada hanya melihat foto hasil fan-out, dan foto lama dari akun yang di follow hilang dari feed
+func (u *timelineUsecaseImpl) backfill(ctx context.Context, userId uint) error {
+	first, err := u.redisRepository.SetNX(ctx, timelineBuiltKey(userId), "1", 0)
+	if err != nil {
+		log.Printf("[backfill, SetNX] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	if !first {
+		return nil
+	}
+
+	photos, err := u.photoRepository.FindRecentEntriesByFollowerId(ctx, userId, int(u.cfg.MaxLength))
+	if err != nil {
+		log.Printf("[backfill, FindRecentEntriesByFollowerId] with error detail %v", err.Error())
+		u.resetBackfill(ctx, userId)
+		return err
+	}
+
+	members := make([]repository.ZMember, 0, len(photos))
+	for _, photo := range photos {
+		members = append(members, repository.ZMember{
+			Member: photo.ID,
+			Score:  timelineScore(*photo.CreatedAt),
+		})
+	}
+
+	err = u.redisRepository.ZAddManyCapped(ctx, timelineKey(userId), members, u.cfg.MaxLength)
+	if err != nil {
+		log.Printf("[backfill, ZAddManyCapped] with error detail %v", err.Error())
+		u.resetBackfill(ctx, userId)
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// resetBackfill menghapus penanda supaya backfill diulang di pembacaan berikutnya
+func (u *timelineUsecaseImpl) resetBackfill(ctx context.Context, userId uint) {
+	err := u.redisRepository.Del(ctx, timelineBuiltKey(userId))
+	if err != nil {
+		log.Printf("[resetBackfill, Del] with error detail %v", err.Error())
+	}
+}
+
+// AddFollowing implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) AddFollowing(ctx context.Context, followerId, followingId uint) error {
+	totalFollower, err := u.followRepository.CountFollowerByUserId(ctx, followingId)
+	if err != nil {
+		log.Printf("[AddFollowing, CountFollowerByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	// foto user populer diambil saat feed dibaca, tidak perlu disalin ke timeline
+	if totalFollower > u.cfg.FanOutThreshold {
+		return nil
+	}
+
+	photos, err := u.photoRepository.FindRecentEntriesByUserId(ctx, followingId, int(u.cfg.MaxLength))
+	if err != nil {
+		log.Printf("[AddFollowing, FindRecentEntriesByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	members := make([]repository.ZMember, 0, len(photos))
+	for _, photo := range photos {
+		members = append(members, repository.ZMember{
+			Member: photo.ID,
+			Score:  timelineScore(*photo.CreatedAt),
+		})
+	}
+
+	err = u.redisRepository.ZAddManyCapped(ctx, timelineKey(followerId), members, u.cfg.MaxLength)
+	if err != nil {
+		log.Printf("[AddFollowing, ZAddManyCapped] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// RemoveFollowing implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) RemoveFollowing(ctx context.Context, followerId, followingId uint) error {
+	photoIds, err := u.photoRepository.FindIdsByUserId(ctx, followingId)
+	if err != nil {
+		log.Printf("[RemoveFollowing, FindIdsByUserId] with error detail %v", err.Error())
+		return err
+	}
+
+	err = u.redisRepository.ZRemMany(ctx, timelineKey(followerId), photoIds)
+	if err != nil {
+		log.Printf("[RemoveFollowing, ZRemMany] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// RemoveUser implements usecase.TimelineUsecase.
+// Semua follower diproses tanpa melihat threshold fan-out karena threshold bisa berubah setelah foto di fan-out
+func (u *timelineUsecaseImpl) RemoveUser(ctx context.Context, userId uint, followerIds []uint, photoIds []string) error {
+	if len(photoIds) > 0 {
+		for _, followerId := range followerIds {
+			err := u.redisRepository.ZRemMany(ctx, timelineKey(followerId), photoIds)
+			if err != nil {
+				log.Printf("[RemoveUser, ZRemMany] with error detail %v", err.Error())
+				return helpers.ErrRepository
+			}
+		}
+	}
+
+	err := u.redisRepository.Del(ctx, timelineKey(userId), timelineBuiltKey(userId))
+	if err != nil {
+		log.Printf("[RemoveUser, Del] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// GetFanOutOnReadUserIds implements usecase.TimelineUsecase.
+func (u *timelineUsecaseImpl) GetFanOutOnReadUserIds(ctx context.Context, userId uint) ([]uint, error) {
+	userIds, err := u.followRepository.FindFollowingIdsWithMinFollowers(ctx, userId, u.cfg.FanOutThreshold)
+	if err != nil {
+		log.Printf("[GetFanOutOnReadUserIds, FindFollowingIdsWithMinFollowers] with error detail %v", err.Error())
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+// followerTimelineKeys mengembalikan key timeline semua follower,
+// kosong jika user melewati threshold fan-out
+func (u *timelineUsecaseImpl) followerTimelineKeys(ctx context.Context, userId uint) ([]string, error) {
+	totalFollower, err := u.followRepository.CountFollowerByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalFollower > u.cfg.FanOutThreshold {
+		return nil, nil
+	}
+
+	followerIds, err := u.followRepository.FindFollowerIdsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(followerIds))
+	for _, followerId := range followerIds {
+		keys = append(keys, timelineKey(followerId))
+	}
+
+	return keys, nil
+}
+
+func timelineKey(userId uint) string {
+	return fmt.Sprintf("timeline:%d", userId)
+}
+
+// timelineBuiltKey menandai timeline yang sudah diisi dari database
+func timelineBuiltKey(userId uint) string {
+	return fmt.Sprintf("timeline_built:%d", userId)
+}
+
+func timelineScore(createdAt time.Time) float64 {
+	return float64(createdAt.UnixMicro())
+}
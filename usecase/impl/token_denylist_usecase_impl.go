@@ -0,0 +1,129 @@
+package impl
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
+	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/dgrijalva/jwt-go"
+)
+
+type tokenDenylistUsecaseImpl struct {
+	redisRepository   repository.RedisRepository
+	accessTokenExpiry time.Duration
+}
+
+func NewTokenDenylistUsecaseImpl(redisRepository repository.RedisRepository, accessTokenExpiry time.Duration) usecase.TokenDenylistUsecase {
+	return &tokenDenylistUsecaseImpl{
+		redisRepository:   redisRepository,
+		accessTokenExpiry: accessTokenExpiry,
+	}
+}
+
+// RevokeToken implements usecase.TokenDenylistUsecase.
+func (u *tokenDenylistUsecaseImpl) RevokeToken(ctx context.Context, userData jwt.MapClaims) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	jti, _, expiresAt := helpers.GetTokenId(userData)
+	if jti == "" {
+		return nil
+	}
+
+	// token yang sudah kedaluwarsa tidak perlu disimpan
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	err := u.redisRepository.Set(ctx, denylistTokenKey(jti), "1", ttl)
+	if err != nil {
+		log.Printf("[RevokeToken, Set] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// RevokeUserTokens implements usecase.TokenDenylistUsecase.
+// Dipakai saat jti token user tidak diketahui, misalnya reset password,
+// ttl-nya cukup selama umur access token karena token yang lebih tua sudah kedaluwarsa
+func (u *tokenDenylistUsecaseImpl) RevokeUserTokens(ctx context.Context, userId uint) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	revokedBefore := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	err := u.redisRepository.Set(ctx, denylistUserKey(userId), revokedBefore, u.accessTokenExpiry)
+	if err != nil {
+		log.Printf("[RevokeUserTokens, Set] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// RevokeSessionTokens implements usecase.TokenDenylistUsecase.
+// Family tidak bisa dipakai lagi setelah dicabut, jadi semua access token-nya langsung ditolak
+func (u *tokenDenylistUsecaseImpl) RevokeSessionTokens(ctx context.Context, familyId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := u.redisRepository.Set(ctx, denylistSessionKey(familyId), "1", u.accessTokenExpiry)
+	if err != nil {
+		log.Printf("[RevokeSessionTokens, Set] with error detail %v", err.Error())
+		return helpers.ErrRepository
+	}
+
+	return nil
+}
+
+// IsRevoked implements usecase.TokenDenylistUsecase.
+func (u *tokenDenylistUsecaseImpl) IsRevoked(ctx context.Context, userData jwt.MapClaims) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	actor := helpers.GetActor(userData)
+	jti, issuedAt, _ := helpers.GetTokenId(userData)
+	familyId := helpers.GetSessionId(userData)
+
+	values, err := u.redisRepository.MGet(ctx, denylistTokenKey(jti), denylistUserKey(actor.Id), denylistSessionKey(familyId))
+	if err != nil {
+		log.Printf("[IsRevoked, MGet] with error detail %v", err.Error())
+		return false, helpers.ErrRepository
+	}
+
+	if jti != "" && values[0] != nil {
+		return true, nil
+	}
+
+	// token yang terbit di milidetik yang sama dengan pencabutan ikut ditolak
+	if revokedBefore, ok := values[1].(string); ok {
+		unixMilli, err := strconv.ParseInt(revokedBefore, 10, 64)
+		if err == nil && issuedAt.UnixMilli() <= unixMilli {
+			return true, nil
+		}
+	}
+
+	if familyId != "" && values[2] != nil {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func denylistTokenKey(jti string) string {
+	return "denylist:token:" + jti
+}
+
+func denylistSessionKey(familyId string) string {
+	return "denylist:session:" + familyId
+}
+
+func denylistUserKey(userId uint) string {
+	return "denylist:user:" + strconv.FormatUint(uint64(userId), 10)
+}
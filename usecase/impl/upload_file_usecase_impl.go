@@ -3,65 +3,753 @@ package impl
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
 	"github.com/ariwiraa/my-gram/helpers"
+	"github.com/ariwiraa/my-gram/repository"
 	"github.com/ariwiraa/my-gram/usecase"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	uploadSessionTTL     = 24 * time.Hour
+	uploadSessionLockTTL = time.Minute
+
+	// maxOpenUploadSessions dan maxReservedUploadFiles membatasi session resumable yang belum selesai
+	// per user, supaya file .part tidak bisa dipakai untuk memenuhi disk
+	maxOpenUploadSessions  = 5
+	maxReservedUploadFiles = 2
+
+	// presignedUploadExpiry sengaja pendek, form presigned hanya untuk satu kali upload
+	presignedUploadExpiry = 15 * time.Minute
+
+	// confirmedUploadTTL adalah lama konfirmasi ulang file presigned masih mengembalikan media yang sama
+	confirmedUploadTTL = 24 * time.Hour
+
+	// duplicateMediaWindow adalah rentang foto milik user yang dibandingkan saat upload
+	duplicateMediaWindow = 30 * 24 * time.Hour
 )
 
 type uploadFileUsecaseImpl struct {
-	cloudinary usecase.CloudinaryUsecase
+	storageProvider      usecase.StorageProvider
+	redisRepository      repository.RedisRepository
+	mediaRepository      repository.MediaRepository
+	photoMediaRepository repository.PhotoMediaRepository
+	tempDir              string
 }
 
-func NewUploadFileImpl(cloudinary usecase.CloudinaryUsecase) usecase.UploadFileUsecase {
+func NewUploadFileImpl(storageProvider usecase.StorageProvider, redisRepository repository.RedisRepository, mediaRepository repository.MediaRepository, photoMediaRepository repository.PhotoMediaRepository, tempDir string) usecase.UploadFileUsecase {
 	return &uploadFileUsecaseImpl{
-		cloudinary: cloudinary,
+		storageProvider:      storageProvider,
+		redisRepository:      redisRepository,
+		mediaRepository:      mediaRepository,
+		photoMediaRepository: photoMediaRepository,
+		tempDir:              tempDir,
 	}
 }
 
 // Upload implements usecase.UploadFile.
-func (u *uploadFileUsecaseImpl) Upload(ctx context.Context, fileHeader *multipart.FileHeader, userId uint) (string, error) {
+func (u *uploadFileUsecaseImpl) Upload(ctx context.Context, fileHeader *multipart.FileHeader, userId uint) (*response.UploadFileResponse, error) {
 
 	file, err := fileHeader.Open()
 	if err != nil {
 		log.Printf("[UploadFile, Upload, Open] error with detail %v", err.Error())
-		return "", err
+		return nil, err
 	}
 	defer file.Close()
 
-	fileSupported, err := helpers.IsImageFile(file)
-	if err != nil {
-		log.Printf("[UploadFile, Upload, IsImageFile] error with detail %v", err.Error())
-		return "", err
-	}
+	return u.store(ctx, file, fileHeader.Size, userId)
+}
 
-	if !fileSupported {
-		return "", helpers.ErrFileNotSupported
+// store memvalidasi, memproses lalu menyimpan file. Dipakai oleh upload biasa dan finalize resumable upload
+func (u *uploadFileUsecaseImpl) store(ctx context.Context, file io.ReadSeeker, size int64, userId uint) (*response.UploadFileResponse, error) {
+	contentType, err := helpers.DetectMediaType(file)
+	if err != nil {
+		log.Printf("[UploadFile, store, DetectMediaType] error with detail %v", err.Error())
+		return nil, err
 	}
 
-	err = helpers.IsFileSizeValid(fileHeader)
+	err = helpers.IsFileSizeValid(size, contentType)
 	if err != nil {
-		return "", helpers.ErrorFileSizeNotValid
+		return nil, helpers.ErrorFileSizeNotValid
 	}
 
 	buffer := bytes.NewBuffer(nil)
 
 	_, err = io.Copy(buffer, file)
 	if err != nil {
-		log.Printf("[UploadFileUsecase, Upload, Copy] error with detail %v", err.Error())
-		return "", err
+		log.Printf("[UploadFileUsecase, store, Copy] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	processed, err := helpers.ProcessMedia(ctx, buffer.Bytes(), contentType)
+	if err != nil {
+		log.Printf("[UploadFile, store, ProcessMedia] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	// repost gambar yang sama oleh user yang sama ditolak sebelum file disimpan
+	perceptualHash := int64(processed.PerceptualHash)
+	if perceptualHash != 0 {
+		duplicate, err := u.photoMediaRepository.HasSimilarByUser(ctx, userId, perceptualHash, helpers.PERCEPTUAL_HASH_MAX_DISTANCE, time.Now().Add(-duplicateMediaWindow))
+		if err != nil {
+			return nil, err
+		}
+
+		if duplicate {
+			return nil, helpers.ErrDuplicateMedia
+		}
+	}
+
+	pathDestination := userImagePath(userId)
+
+	uploadedFile := &response.UploadFileResponse{
+		MediaType:     processed.MediaType,
+		Width:         processed.Full.Width,
+		Height:        processed.Full.Height,
+		BlurHash:      processed.BlurHash,
+		DominantColor: processed.DominantColor,
+		Duration:      processed.Duration.Seconds(),
 	}
 
-	pathDestination := fmt.Sprintf("%d-images", userId)
+	renditions := []struct {
+		rendition *helpers.MediaRendition
+		url       *string
+	}{
+		{&processed.Full, &uploadedFile.PhotoUrl},
+		{processed.Poster, &uploadedFile.PosterUrl},
+		{&processed.Medium, &uploadedFile.MediumUrl},
+		{&processed.Thumbnail, &uploadedFile.ThumbnailUrl},
+	}
+
+	urls := make([]string, 0, len(renditions))
+	for _, item := range renditions {
+		// poster hanya ada untuk video dan webp animasi tidak punya preview
+		if item.rendition == nil || len(item.rendition.Content) == 0 {
+			continue
+		}
+
+		url, err := u.storageProvider.Upload(ctx, bytes.NewReader(item.rendition.Content), pathDestination)
+		if err != nil {
+			log.Printf("[UploadFile, store, Upload] error with detail %v", err.Error())
+			u.removeUploaded(ctx, urls)
+			return nil, err
+		}
 
-	url, err := u.cloudinary.Upload(ctx, buffer, pathDestination)
+		urls = append(urls, url)
+		*item.url = url
+	}
+
+	err = u.createMedia(ctx, userId, contentType, size, perceptualHash, uploadedFile)
 	if err != nil {
-		log.Printf("[UploadFile, Upload, Upload] error with detail %v", err.Error())
-		return url, err
+		u.removeUploaded(ctx, urls)
+		return nil, err
+	}
+
+	return uploadedFile, nil
+}
+
+// removeUploaded membersihkan rendition yang sudah terlanjur tersimpan ketika rendition berikutnya gagal diupload
+func (u *uploadFileUsecaseImpl) removeUploaded(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		err := u.storageProvider.Remove(ctx, url)
+		if err != nil {
+			log.Printf("[UploadFile, removeUploaded, Remove] error with detail %v", err.Error())
+		}
+	}
+}
+
+// CreateSession implements usecase.UploadFileUsecase.
+func (u *uploadFileUsecaseImpl) CreateSession(ctx context.Context, userId uint, length int64) (*domain.UploadSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if length <= 0 {
+		return nil, helpers.ErrUploadLengthInvalid
+	}
+
+	// tipe file belum diketahui, jadi dibatasi dengan batas terbesar dulu
+	if length > helpers.MaxUploadSize() {
+		return nil, helpers.ErrorFileSizeNotValid
+	}
+
+	// pengecekan kuota dan pembuatan session dikunci per user supaya request paralel tidak melewati batas
+	lock, locked, err := acquireLock(ctx, u.redisRepository, userUploadSessionsKey(userId)+":lock", uploadSessionLockTTL)
+	if err != nil {
+		log.Printf("[UploadFile, CreateSession, acquireLock] error with detail %v", err.Error())
+		return nil, err
+	}
+	if !locked {
+		return nil, helpers.ErrUploadSessionBusy
+	}
+	defer lock.release()
+
+	openSessions, reservedBytes, err := u.openSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if openSessions >= maxOpenUploadSessions || reservedBytes+length > maxReservedUploadFiles*helpers.MaxUploadSize() {
+		return nil, helpers.ErrUploadQuotaExceeded
+	}
+
+	err = os.MkdirAll(u.tempDir, 0o755)
+	if err != nil {
+		log.Printf("[UploadFile, CreateSession, MkdirAll] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &domain.UploadSession{
+		Id:        uuid.NewString(),
+		UserId:    userId,
+		Length:    length,
+		CreatedAt: now,
+	}
+
+	// file kosong dibuat di awal supaya chunk pertama dan berikutnya diperlakukan sama
+	file, err := os.Create(u.sessionFilePath(session.Id))
+	if err != nil {
+		log.Printf("[UploadFile, CreateSession, Create] error with detail %v", err.Error())
+		return nil, err
+	}
+	file.Close()
+
+	err = u.saveSession(ctx, session)
+	if err != nil {
+		os.Remove(u.sessionFilePath(session.Id))
+		return nil, err
+	}
+
+	member := repository.ZMember{Member: session.Id, Score: float64(length)}
+	err = u.redisRepository.ZAddManyCapped(ctx, userUploadSessionsKey(userId), []repository.ZMember{member}, maxOpenUploadSessions)
+	if err != nil {
+		log.Printf("[UploadFile, CreateSession, ZAddManyCapped] error with detail %v", err.Error())
+		u.removeSession(ctx, session.Id, userId)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// openSessions menghitung session milik user yang masih hidup beserta total ukuran yang dipesan.
+// Session yang sudah kedaluwarsa di redis dibuang dari daftar
+func (u *uploadFileUsecaseImpl) openSessions(ctx context.Context, userId uint) (int, int64, error) {
+	key := userUploadSessionsKey(userId)
+
+	members, err := u.redisRepository.ZRevRangeByScore(ctx, key, "-inf", "+inf", 0)
+	if err != nil {
+		log.Printf("[UploadFile, openSessions, ZRevRangeByScore] error with detail %v", err.Error())
+		return 0, 0, err
+	}
+
+	if len(members) == 0 {
+		return 0, 0, nil
+	}
+
+	sessionKeys := make([]string, 0, len(members))
+	for _, member := range members {
+		sessionKeys = append(sessionKeys, uploadSessionKey(member.Member))
 	}
 
-	return url, err
+	values, err := u.redisRepository.MGet(ctx, sessionKeys...)
+	if err != nil {
+		log.Printf("[UploadFile, openSessions, MGet] error with detail %v", err.Error())
+		return 0, 0, err
+	}
+
+	var openSessions int
+	var reservedBytes int64
+	var expired []string
+	for i, member := range members {
+		if values[i] == nil {
+			expired = append(expired, member.Member)
+			continue
+		}
+
+		openSessions++
+		reservedBytes += int64(member.Score)
+	}
+
+	err = u.redisRepository.ZRemMany(ctx, key, expired)
+	if err != nil {
+		log.Printf("[UploadFile, openSessions, ZRemMany] error with detail %v", err.Error())
+	}
+
+	return openSessions, reservedBytes, nil
+}
+
+// GetSession implements usecase.UploadFileUsecase.
+func (u *uploadFileUsecaseImpl) GetSession(ctx context.Context, id string, userId uint) (*domain.UploadSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return u.findSession(ctx, id, userId)
+}
+
+// WriteChunk implements usecase.UploadFileUsecase.
+// Tidak memakai timeout 5 detik karena lama upload chunk bergantung pada jaringan client
+func (u *uploadFileUsecaseImpl) WriteChunk(ctx context.Context, id string, userId uint, offset int64, chunk io.Reader) (*domain.UploadSession, error) {
+	lock, err := u.lockSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	// lock diperpanjang selama chunk ditulis, kalau lock hilang penulisan dihentikan
+	writeCtx, stopKeepAlive := lock.keepAlive(ctx)
+	defer stopKeepAlive()
+
+	session, err := u.findSession(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != session.Offset {
+		return session, helpers.ErrUploadOffsetMismatch
+	}
+
+	file, err := os.OpenFile(u.sessionFilePath(id), os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		log.Printf("[UploadFile, WriteChunk, OpenFile] error with detail %v", err.Error())
+		return nil, err
+	}
+	defer file.Close()
+
+	// buang sisa tulisan dari request sebelumnya yang gagal di tengah jalan
+	err = file.Truncate(offset)
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		log.Printf("[UploadFile, WriteChunk, Truncate] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	remaining := session.Length - session.Offset
+	written, copyErr := io.Copy(file, io.LimitReader(contextReader{ctx: writeCtx, reader: chunk}, remaining+1))
+
+	// request lain sudah memegang lock, file dan session jadi miliknya
+	if writeCtx.Err() != nil && ctx.Err() == nil {
+		return nil, helpers.ErrUploadSessionBusy
+	}
+
+	if written > remaining {
+		file.Truncate(offset)
+		return session, helpers.ErrUploadChunkTooLarge
+	}
+
+	// byte yang sudah diterima tetap disimpan walaupun koneksi putus, client cukup lanjut dari offset terakhir
+	session.Offset += written
+
+	err = u.saveSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if copyErr != nil {
+		log.Printf("[UploadFile, WriteChunk, Copy] error with detail %v", copyErr.Error())
+		return session, copyErr
+	}
+
+	return session, nil
+}
+
+// FinalizeSession implements usecase.UploadFileUsecase.
+func (u *uploadFileUsecaseImpl) FinalizeSession(ctx context.Context, id string, userId uint) (*response.UploadFileResponse, error) {
+	lock, err := u.lockSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	// store bisa lebih lama dari ttl lock (ffmpeg dan beberapa upload), kalau lock hilang proses dihentikan
+	// supaya finalize yang diulang tidak memproses file .part yang sama dua kali
+	ctx, stopKeepAlive := lock.keepAlive(ctx)
+	defer stopKeepAlive()
+
+	session, err := u.findSession(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Offset != session.Length {
+		return nil, helpers.ErrUploadIncomplete
+	}
+
+	file, err := os.Open(u.sessionFilePath(id))
+	if err != nil {
+		log.Printf("[UploadFile, FinalizeSession, Open] error with detail %v", err.Error())
+		return nil, helpers.ErrUploadSessionNotFound
+	}
+	defer file.Close()
+
+	// session tetap disimpan kalau gagal supaya finalize bisa diulang tanpa upload ulang
+	uploadedFile, err := u.store(ctx, file, session.Length, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	u.removeSession(ctx, id, userId)
+
+	return uploadedFile, nil
+}
+
+// DeleteSession implements usecase.UploadFileUsecase.
+func (u *uploadFileUsecaseImpl) DeleteSession(ctx context.Context, id string, userId uint) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := u.findSession(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	u.removeSession(ctx, id, userId)
+
+	return nil
+}
+
+func (u *uploadFileUsecaseImpl) findSession(ctx context.Context, id string, userId uint) (*domain.UploadSession, error) {
+	value, err := u.redisRepository.Get(ctx, uploadSessionKey(id))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, helpers.ErrUploadSessionNotFound
+		}
+		log.Printf("[UploadFile, findSession, Get] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	session := new(domain.UploadSession)
+	err = json.Unmarshal([]byte(value.(string)), session)
+	if err != nil {
+		log.Printf("[UploadFile, findSession, Unmarshal] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	// session milik user lain diperlakukan seperti tidak ada
+	if session.UserId != userId {
+		return nil, helpers.ErrUploadSessionNotFound
+	}
+
+	return session, nil
+}
+
+// saveSession menyimpan state session dan memperpanjang masa berlakunya setiap ada chunk masuk
+func (u *uploadFileUsecaseImpl) saveSession(ctx context.Context, session *domain.UploadSession) error {
+	session.ExpiresAt = time.Now().Add(uploadSessionTTL)
+
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	err = u.redisRepository.Set(ctx, uploadSessionKey(session.Id), string(value), uploadSessionTTL)
+	if err != nil {
+		log.Printf("[UploadFile, saveSession, Set] error with detail %v", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (u *uploadFileUsecaseImpl) removeSession(ctx context.Context, id string, userId uint) {
+	err := os.Remove(u.sessionFilePath(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[UploadFile, removeSession, Remove] error with detail %v", err.Error())
+	}
+
+	err = u.redisRepository.Del(ctx, uploadSessionKey(id))
+	if err != nil {
+		log.Printf("[UploadFile, removeSession, Del] error with detail %v", err.Error())
+	}
+
+	err = u.redisRepository.ZRemMany(ctx, userUploadSessionsKey(userId), []string{id})
+	if err != nil {
+		log.Printf("[UploadFile, removeSession, ZRemMany] error with detail %v", err.Error())
+	}
+}
+
+// lockSession mencegah dua request menulis ke session yang sama secara bersamaan
+func (u *uploadFileUsecaseImpl) lockSession(ctx context.Context, id string) (*redisLock, error) {
+	lock, locked, err := acquireLock(ctx, u.redisRepository, uploadSessionKey(id)+":lock", uploadSessionLockTTL)
+	if err != nil {
+		log.Printf("[UploadFile, lockSession, acquireLock] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	if !locked {
+		return nil, helpers.ErrUploadSessionBusy
+	}
+
+	return lock, nil
+}
+
+// contextReader berhenti membaca body chunk begitu context dibatalkan
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.reader.Read(p)
+}
+
+// sessionFilePath memakai filepath.Base supaya id dari url tidak bisa keluar dari tempDir
+func (u *uploadFileUsecaseImpl) sessionFilePath(id string) string {
+	return filepath.Join(u.tempDir, filepath.Base(id)+".part")
+}
+
+func uploadSessionKey(id string) string {
+	return "upload_session:" + id
+}
+
+func userUploadSessionsKey(userId uint) string {
+	return fmt.Sprintf("upload_sessions:user:%d", userId)
+}
+
+// PresignUpload implements usecase.UploadFileUsecase.
+func (u *uploadFileUsecaseImpl) PresignUpload(ctx context.Context, payload request.PresignUploadRequest, userId uint) (*response.PresignedUploadResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limit, ok := helpers.MediaLimits[payload.ContentType]
+	if !ok {
+		return nil, helpers.ErrFileNotSupported
+	}
+
+	if payload.Size <= 0 {
+		return nil, helpers.ErrUploadLengthInvalid
+	}
+
+	err := helpers.IsFileSizeValid(payload.Size, payload.ContentType)
+	if err != nil {
+		return nil, helpers.ErrorFileSizeNotValid
+	}
+
+	presigned, err := u.storageProvider.PresignUpload(ctx, userImagePath(userId), payload.ContentType, limit.MaxSize*1024*1024, presignedUploadExpiry)
+	if err != nil {
+		log.Printf("[UploadFile, PresignUpload, PresignUpload] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	return &response.PresignedUploadResponse{
+		UploadUrl: presigned.UploadURL,
+		Method:    http.MethodPost,
+		Fields:    presigned.Fields,
+		FileUrl:   presigned.FileURL,
+		ExpiresAt: presigned.ExpiresAt,
+	}, nil
+}
+
+// ConfirmUpload implements usecase.UploadFileUsecase.
+// File presigned diunduh lalu melewati pipeline yang sama dengan upload biasa, jadi isi file dideteksi ulang,
+// metadata dibuang, durasi dibatasi, duplikat dicek dan rendition dibuat. Yang disimpan adalah hasil proses,
+// file asli dari client dihapus. Tidak memakai timeout 5 detik karena unduh dan proses video bisa lama
+func (u *uploadFileUsecaseImpl) ConfirmUpload(ctx context.Context, payload request.ConfirmUploadRequest, userId uint) (*response.UploadFileResponse, error) {
+	key, err := u.storageProvider.ObjectKey(payload.Url)
+	if err != nil {
+		return nil, helpers.ErrFileNotFound
+	}
+
+	// file di folder user lain dianggap tidak ada
+	if !strings.HasPrefix(key, storageRootFolder+"/"+userImagePath(userId)+"/") {
+		return nil, helpers.ErrFileNotFound
+	}
+
+	// konfirmasi file yang sama dikunci supaya request paralel tidak memproses file dua kali
+	lock, locked, err := acquireLock(ctx, u.redisRepository, confirmedUploadKey(key)+":lock", uploadSessionLockTTL)
+	if err != nil {
+		log.Printf("[UploadFile, ConfirmUpload, acquireLock] error with detail %v", err.Error())
+		return nil, err
+	}
+	if !locked {
+		return nil, helpers.ErrUploadConfirmBusy
+	}
+	defer lock.release()
+
+	ctx, stopKeepAlive := lock.keepAlive(ctx)
+	defer stopKeepAlive()
+
+	// konfirmasi ulang, misalnya client retry setelah timeout, mengembalikan media yang sudah dibuat
+	confirmed, err := u.findConfirmedUpload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if confirmed != nil {
+		return confirmed, nil
+	}
+
+	object, err := u.storageProvider.Stat(ctx, payload.Url)
+	if err != nil {
+		log.Printf("[UploadFile, ConfirmUpload, Stat] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	// cloudinary tidak membatasi ukuran di presigned form, jadi file raksasa ditolak sebelum diunduh
+	if object.Size > helpers.MaxUploadSize() {
+		u.removeUploaded(ctx, []string{payload.Url})
+		return nil, helpers.ErrorFileSizeNotValid
+	}
+
+	file, err := u.storageProvider.Open(ctx, payload.Url)
+	if err != nil {
+		log.Printf("[UploadFile, ConfirmUpload, Open] error with detail %v", err.Error())
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, helpers.MaxUploadSize()+1))
+	if err != nil {
+		log.Printf("[UploadFile, ConfirmUpload, ReadAll] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	if int64(len(content)) > helpers.MaxUploadSize() {
+		u.removeUploaded(ctx, []string{payload.Url})
+		return nil, helpers.ErrorFileSizeNotValid
+	}
+
+	uploadedFile, err := u.store(ctx, bytes.NewReader(content), int64(len(content)), userId)
+	if err != nil {
+		// file yang tidak lolos validasi langsung dihapus supaya tidak menumpuk di storage,
+		// error lain membiarkan file supaya konfirmasi bisa diulang
+		if isMediaRejected(err) {
+			u.removeUploaded(ctx, []string{payload.Url})
+		}
+		return nil, err
+	}
+
+	err = u.redisRepository.Set(ctx, confirmedUploadKey(key), uploadedFile.Id, confirmedUploadTTL)
+	if err != nil {
+		log.Printf("[UploadFile, ConfirmUpload, Set] error with detail %v", err.Error())
+	}
+
+	u.removeUploaded(ctx, []string{payload.Url})
+
+	return uploadedFile, nil
+}
+
+// findConfirmedUpload mengembalikan media hasil konfirmasi sebelumnya, nil kalau file belum pernah dikonfirmasi
+func (u *uploadFileUsecaseImpl) findConfirmedUpload(ctx context.Context, key string) (*response.UploadFileResponse, error) {
+	value, err := u.redisRepository.Get(ctx, confirmedUploadKey(key))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		log.Printf("[UploadFile, findConfirmedUpload, Get] error with detail %v", err.Error())
+		return nil, err
+	}
+
+	media, err := u.mediaRepository.FindByIds(ctx, []string{value.(string)})
+	if err != nil {
+		return nil, err
+	}
+
+	// media sudah dihapus reaper, file presigned-nya juga sudah tidak ada
+	if len(media) == 0 {
+		return nil, helpers.ErrFileNotFound
+	}
+
+	return toUploadFileResponse(media[0]), nil
+}
+
+func confirmedUploadKey(key string) string {
+	return "upload_confirmed:" + key
+}
+
+func toUploadFileResponse(media domain.Media) *response.UploadFileResponse {
+	return &response.UploadFileResponse{
+		Id:            media.ID,
+		MediaType:     media.MediaType,
+		PhotoUrl:      media.PhotoUrl,
+		PosterUrl:     media.PosterUrl,
+		MediumUrl:     media.MediumUrl,
+		ThumbnailUrl:  media.ThumbnailUrl,
+		Width:         media.Width,
+		Height:        media.Height,
+		BlurHash:      media.BlurHash,
+		DominantColor: media.DominantColor,
+		Duration:      media.Duration,
+	}
+}
+
+// isMediaRejected menandai error karena isi file, bukan karena storage atau database
+func isMediaRejected(err error) bool {
+	for _, target := range []error{
+		helpers.ErrFileNotSupported,
+		helpers.ErrorFileSizeNotValid,
+		helpers.ErrImageTooLarge,
+		helpers.ErrMediaTooLong,
+		helpers.ErrVideoProcessing,
+		helpers.ErrDuplicateMedia,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createMedia mencatat hasil upload sebagai media yang belum di-claim.
+// PublicId adalah key file di storage menurut driver yang dipakai
+func (u *uploadFileUsecaseImpl) createMedia(ctx context.Context, userId uint, contentType string, size int64, perceptualHash int64, uploadedFile *response.UploadFileResponse) error {
+	publicId, err := u.storageProvider.ObjectKey(uploadedFile.PhotoUrl)
+	if err != nil {
+		log.Printf("[UploadFile, createMedia, ObjectKey] error with detail %v", err.Error())
+		return err
+	}
+
+	media := domain.Media{
+		ID:            uuid.NewString(),
+		UserId:        userId,
+		PublicId:      publicId,
+		MimeType:      contentType,
+		Size:          size,
+		MediaType:     uploadedFile.MediaType,
+		PhotoUrl:      uploadedFile.PhotoUrl,
+		PosterUrl:     uploadedFile.PosterUrl,
+		MediumUrl:     uploadedFile.MediumUrl,
+		ThumbnailUrl:  uploadedFile.ThumbnailUrl,
+		Width:         uploadedFile.Width,
+		Height:        uploadedFile.Height,
+		BlurHash:      uploadedFile.BlurHash,
+		DominantColor: uploadedFile.DominantColor,
+		Duration:      uploadedFile.Duration,
+
+		PerceptualHash: perceptualHash,
+	}
+
+	err = u.mediaRepository.Create(ctx, media)
+	if err != nil {
+		return err
+	}
+
+	uploadedFile.Id = media.ID
+
+	return nil
+}
+
+// userImagePath adalah folder upload milik user, sama dengan yang dipakai getPublicId
+func userImagePath(userId uint) string {
+	return fmt.Sprintf("%d-images", userId)
 }
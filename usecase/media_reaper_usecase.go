@@ -0,0 +1,19 @@
+package usecase
+
+import "context"
+
+// MediaReaperResult adalah jumlah file yang dibersihkan dalam satu kali jalan
+type MediaReaperResult struct {
+	UnclaimedMedia int
+	OrphanObjects  int
+	TempFiles      int
+	Failed         int
+}
+
+type MediaReaperUsecase interface {
+	// Reap menghapus media yang tidak pernah di-claim, file storage yang tidak tercatat di database
+	// dan sisa file resumable upload yang session-nya sudah kedaluwarsa
+	Reap(ctx context.Context) (*MediaReaperResult, error)
+	// Run menjalankan Reap setiap interval sampai ctx dibatalkan
+	Run(ctx context.Context)
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+)
+
+// MentionStreamKey adalah redis stream yang dibaca oleh consumer notifikasi,
+// setiap entry berisi field payload dengan domain.MentionEvent dalam bentuk json
+const MentionStreamKey = "events:mention"
+
+type MentionUsecase interface {
+	// Resolve mencari @username di text dan mengembalikan mention untuk username yang terdaftar,
+	// username yang tidak ditemukan dibiarkan sebagai text biasa
+	Resolve(ctx context.Context, text string) ([]domain.Mention, error)
+	// Publish mengirim event mention ke stream notifikasi. User yang ada di previous sudah pernah
+	// dinotifikasi untuk text yang sama, dan author tidak dinotifikasi karena me-mention dirinya sendiri
+	Publish(ctx context.Context, event domain.MentionEvent, mentions []domain.Mention, previous []domain.Mention) error
+}
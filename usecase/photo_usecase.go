@@ -6,13 +6,16 @@ import (
 	"github.com/ariwiraa/my-gram/domain"
 	"github.com/ariwiraa/my-gram/domain/dtos/request"
 	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
 )
 
 type PhotoUsecase interface {
 	Create(ctx context.Context, payload request.PhotoRequest, userId uint) (*response.PhotoResponse, error)
 	GetById(ctx context.Context, id string) (*response.PhotoResponse, error)
-	GetAll(ctx context.Context) ([]domain.Photo, error)
-	GetAllPhotosByUserId(ctx context.Context, userId uint) ([]domain.Photo, error)
+	GetAll(ctx context.Context, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error)
+	GetAllPhotosByUserId(ctx context.Context, userId uint, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error)
 	Update(ctx context.Context, payload request.UpdatePhotoRequest, id string, userId uint) (*response.PhotoResponse, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, actor domain.Actor) error
+	// GetSimilar mencari foto lain yang gambarnya mirip, dipakai moderator untuk menemukan repost
+	GetSimilar(ctx context.Context, id string) ([]response.SimilarPhotoResponse, error)
 }
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// StorageObject adalah metadata file yang tersimpan di storage
+type StorageObject struct {
+	Key         string
+	URL         string
+	Size        int64
+	ContentType string
+	UpdatedAt   time.Time
+}
+
+// PresignedUpload adalah url dan field form untuk upload langsung dari client ke storage
+type PresignedUpload struct {
+	UploadURL string
+	Fields    map[string]string
+	// FileURL adalah url publik file setelah client selesai upload
+	FileURL   string
+	ExpiresAt time.Time
+}
+
+// StorageProvider membungkus tempat penyimpanan file upload (cloudinary, local disk atau S3).
+// Semua method menerima url publik yang dikembalikan Upload, karena url itulah yang disimpan di database
+type StorageProvider interface {
+	// @Param file refer to file buffer
+	// @Param pathDestination refer to target directory/bucket in cloud provider
+	Upload(ctx context.Context, file io.Reader, pathDestination string) (uri string, err error)
+	Remove(ctx context.Context, urlString string) (err error)
+	Stat(ctx context.Context, urlString string) (*StorageObject, error)
+	// ObjectKey mengubah url publik menjadi key file di storage, sama dengan StorageObject.Key dari List dan Stat
+	ObjectKey(urlString string) (string, error)
+	// Open membaca isi file, pemanggil wajib menutup reader-nya
+	Open(ctx context.Context, urlString string) (io.ReadCloser, error)
+	// List memanggil fn untuk setiap halaman file hasil upload, dipakai untuk mencari file yang tidak tercatat di database.
+	// Error dari fn menghentikan listing dan dikembalikan apa adanya
+	List(ctx context.Context, fn func(objects []StorageObject) error) error
+	// SignedURL membuat url sementara untuk mengakses file selama expiry
+	SignedURL(ctx context.Context, urlString string, expiry time.Duration) (string, error)
+	// PresignUpload membuat form upload langsung ke storage untuk satu file baru di pathDestination
+	PresignUpload(ctx context.Context, pathDestination, contentType string, maxSize int64, expiry time.Duration) (*PresignedUpload, error)
+}
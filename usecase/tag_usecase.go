@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
+	"github.com/ariwiraa/my-gram/helpers"
+)
+
+type TagUsecase interface {
+	// GetByName mengambil tag beserta jumlah foto yang memakainya, name dinormalisasi dulu
+	GetByName(ctx context.Context, name string) (*response.TagResponse, error)
+	GetPhotosByName(ctx context.Context, name string, page helpers.PageRequest) ([]domain.Photo, helpers.PageInfo, error)
+	// Search dipakai untuk autocomplete, query dinormalisasi lalu dicocokkan sebagai prefix
+	Search(ctx context.Context, query string) ([]response.TagResponse, error)
+	GetTrending(ctx context.Context) ([]domain.TrendingTag, error)
+}
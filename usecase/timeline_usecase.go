@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/helpers"
+)
+
+type TimelineUsecase interface {
+	// FanOutPhoto menambahkan foto ke timeline setiap follower pemilik foto
+	FanOutPhoto(ctx context.Context, photo domain.Photo) error
+	RemovePhoto(ctx context.Context, photo domain.Photo) error
+	// GetPhotoIds mengambil id foto dari timeline user sesuai cursor, timeline diisi dari database saat pertama kali dibaca.
+	// capped bernilai true jika timeline sudah mencapai panjang maksimal,
+	// artinya foto yang lebih lama mungkin sudah terbuang dari redis
+	GetPhotoIds(ctx context.Context, userId uint, page helpers.PageRequest) (photoIds []string, capped bool, err error)
+	// AddFollowing menyalin foto terbaru followingId ke timeline followerId setelah follow
+	AddFollowing(ctx context.Context, followerId, followingId uint) error
+	// RemoveFollowing membuang foto followingId dari timeline followerId setelah unfollow
+	RemoveFollowing(ctx context.Context, followerId, followingId uint) error
+	// RemoveUser membuang foto user yang dihapus dari timeline follower-nya dan menghapus timeline user itu sendiri.
+	// followerIds dan photoIds diambil sebelum data user dihapus dari database
+	RemoveUser(ctx context.Context, userId uint, followerIds []uint, photoIds []string) error
+	// GetFanOutOnReadUserIds mengambil user yang di follow tetapi tidak di fan-out
+	// karena jumlah follower-nya melewati threshold
+	GetFanOutOnReadUserIds(ctx context.Context, userId uint) ([]uint, error)
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type TokenDenylistUsecase interface {
+	// RevokeToken memasukkan jti access token ke denylist sampai token tersebut kedaluwarsa
+	RevokeToken(ctx context.Context, userData jwt.MapClaims) error
+	// RevokeUserTokens menolak semua access token user yang diterbitkan sebelum saat ini
+	RevokeUserTokens(ctx context.Context, userId uint) error
+	// RevokeSessionTokens menolak semua access token yang diterbitkan dari satu family refresh token
+	RevokeSessionTokens(ctx context.Context, familyId string) error
+	IsRevoked(ctx context.Context, userData jwt.MapClaims) (bool, error)
+}
@@ -2,9 +2,23 @@ package usecase
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
+
+	"github.com/ariwiraa/my-gram/domain"
+	"github.com/ariwiraa/my-gram/domain/dtos/request"
+	"github.com/ariwiraa/my-gram/domain/dtos/response"
 )
 
 type UploadFileUsecase interface {
-	Upload(ctx context.Context, fileHeader *multipart.FileHeader, userId uint) (string, error)
+	Upload(ctx context.Context, fileHeader *multipart.FileHeader, userId uint) (*response.UploadFileResponse, error)
+	// resumable upload: buat session, kirim chunk sesuai offset, lalu finalize
+	CreateSession(ctx context.Context, userId uint, length int64) (*domain.UploadSession, error)
+	GetSession(ctx context.Context, id string, userId uint) (*domain.UploadSession, error)
+	WriteChunk(ctx context.Context, id string, userId uint, offset int64, chunk io.Reader) (*domain.UploadSession, error)
+	FinalizeSession(ctx context.Context, id string, userId uint) (*response.UploadFileResponse, error)
+	DeleteSession(ctx context.Context, id string, userId uint) error
+	// upload langsung ke storage: minta form presigned, upload dari client, lalu konfirmasi
+	PresignUpload(ctx context.Context, payload request.PresignUploadRequest, userId uint) (*response.PresignedUploadResponse, error)
+	ConfirmUpload(ctx context.Context, payload request.ConfirmUploadRequest, userId uint) (*response.UploadFileResponse, error)
 }